@@ -2,54 +2,162 @@ package main
 
 import (
 	"context"
+	"os"
+	"time"
 
+	"k8s-descheduler-benchmark/internal/grafana"
 	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/logging"
+	"k8s-descheduler-benchmark/internal/promquery"
+	"k8s-descheduler-benchmark/internal/report"
 	benchsvc "k8s-descheduler-benchmark/internal/service/benchmark"
+	"k8s-descheduler-benchmark/internal/store"
+	"k8s-descheduler-benchmark/internal/tracing"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	podsTotal  int32
-	podCPU     string
-	podMem     string
-	profile    string
-	outputPath string
+	podsTotal           int32
+	podCPU              string
+	podMem              string
+	profile             string
+	outputPath          string
+	deschedulerImages   []string
+	deschedulerMode     string
+	deschedulerSchedule string
+	deschedulerInterval time.Duration
+	traceOptions        tracing.Options
+	grafanaURL          string
+	grafanaToken        string
+	prometheusURL       string
+	prometheusQueries   string
+	prometheusStep      time.Duration
+	outputFormat        string
+	assertExprs         []string
+	junitPath           string
+	noiseNamespaces     int
+	noiseMix            string
+	workloadMix         string
+	priorityThreshold   int32
 )
 
 var benchmarkCmd = &cobra.Command{
 	Use:   "benchmark",
 	Short: "Run a single benchmark scenario",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		exports, err := report.ParseFormats(outputFormat)
+		if err != nil {
+			return err
+		}
+		assertions, err := report.ParseAssertions(assertExprs)
+		if err != nil {
+			return err
+		}
 		client, info, err := k8s.NewClient(clientQPS, clientBurst)
 		if err != nil {
 			return err
 		}
+		cfg := runConfig(info)
+		cfg.Exports = exports
+		cfg.Assertions = assertions
+		cfg.JUnitPath = junitPath
+
+		ctx := context.Background()
+		shutdown, err := tracing.Setup(ctx, traceOptions)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			ctxShutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := shutdown(ctxShutdown); err != nil {
+				logging.GetLogger().Warn("trace export failed", logging.ErrorField(err))
+			}
+		}()
 
 		runner := benchsvc.Runner{
 			Client:      client,
 			Logger:      logging.GetLogger(),
 			MetricsPort: metricsPort,
 		}
-		return runner.Run(context.Background(), benchsvc.RunConfig{
-			PodsTotal:  podsTotal,
-			PodCPU:     podCPU,
-			PodMemory:  podMem,
-			Profile:    profile,
-			OutputPath: outputPath,
-			Context:    info.Context,
-			Server:     info.Server,
-		})
+		if storeDir != "" {
+			runner.Store = store.New(storeDir)
+		}
+		if grafanaURL != "" {
+			token := grafanaToken
+			if token == "" {
+				token = os.Getenv("GRAFANA_TOKEN")
+			}
+			runner.Grafana = grafana.NewClient(grafanaURL, token)
+		}
+		if prometheusURL != "" {
+			prom, err := promquery.NewClient(prometheusURL)
+			if err != nil {
+				return err
+			}
+			runner.Prometheus = prom
+			runner.PrometheusStep = prometheusStep
+			if prometheusQueries != "" {
+				if runner.PrometheusQueries, err = promquery.LoadQueries(prometheusQueries); err != nil {
+					return err
+				}
+			}
+		}
+		return runner.RunImages(ctx, cfg, deschedulerImages)
 	},
 }
 
-func init() {
-	benchmarkCmd.Flags().Int32Var(&podsTotal, "pods", 60, "Number of pods to schedule")
-	benchmarkCmd.Flags().StringVar(&podCPU, "cpu", "100m", "CPU request per pod")
-	benchmarkCmd.Flags().StringVar(&podMem, "mem", "128Mi", "Memory request per pod")
-	benchmarkCmd.Flags().StringVar(&profile, "profile", "baseline", "Descheduler profile (baseline, low-node-utilization, low-node-utilization+duplicates, taints, topology-spread)")
-	benchmarkCmd.Flags().StringVar(&outputPath, "out", "", "Write JSON output to a file (default: results/baseline.json or results/descheduler.json)")
+// runConfig builds the run configuration shared by benchmark and plan.
+func runConfig(info k8s.ClientInfo) benchsvc.RunConfig {
+	return benchsvc.RunConfig{
+		PodsTotal:           podsTotal,
+		PodCPU:              podCPU,
+		PodMemory:           podMem,
+		Profile:             profile,
+		DeschedulerMode:     deschedulerMode,
+		DeschedulerCron:     deschedulerSchedule,
+		DeschedulerInterval: deschedulerInterval,
+		OutputPath:          outputPath,
+		Context:             info.Context,
+		Server:              info.Server,
+		NoiseNamespaces:     noiseNamespaces,
+		NoiseMix:            noiseMix,
+		Mix:                 workloadMix,
+		PriorityThreshold:   priorityThreshold,
+	}
+}
+
+// addRunFlags registers the scenario flags on cmd so benchmark and plan
+// accept the same inputs.
+func addRunFlags(cmd *cobra.Command) {
+	cmd.Flags().Int32Var(&podsTotal, "pods", 60, "Number of pods to schedule")
+	cmd.Flags().StringVar(&podCPU, "cpu", "100m", "CPU request per pod")
+	cmd.Flags().StringVar(&podMem, "mem", "128Mi", "Memory request per pod")
+	cmd.Flags().StringVar(&profile, "profile", "baseline", "Descheduler profile (baseline, low-node-utilization, low-node-utilization+duplicates, taints, topology-spread)")
+	cmd.Flags().StringSliceVar(&deschedulerImages, "descheduler-image", []string{"registry.k8s.io/descheduler/descheduler:v0.32.2"}, "Descheduler image(s); a comma-separated list runs the scenario once per image and writes a comparison summary")
+	cmd.Flags().StringVar(&deschedulerMode, "descheduler-mode", "job", "Descheduler install mode (job, cronjob, deployment)")
+	cmd.Flags().StringVar(&deschedulerSchedule, "descheduler-schedule", "*/1 * * * *", "CronJob schedule when --descheduler-mode=cronjob")
+	cmd.Flags().DurationVar(&deschedulerInterval, "descheduler-interval", time.Minute, "Descheduling interval when --descheduler-mode=deployment")
+	cmd.Flags().IntVar(&noiseNamespaces, "noise-namespaces", 0, "Run background Deployments in this many extra namespaces that the descheduler policy does not target")
+	cmd.Flags().StringVar(&workloadMix, "mix", "", "Benchmark pods by kind and size class instead of --pods, e.g. small=40,sts/small=10,rs/small=4,job/small=4,pod/small=2; kinds are deployment (default), sts, rs, job and pod; append @low, @high or @critical to run at a priority tier")
+	cmd.Flags().Int32Var(&priorityThreshold, "priority-threshold", 0, "Set the DefaultEvictor priorityThreshold of the policy so pods at or above this priority are never evicted (tiers: low=1000, high=100000, critical=1000000)")
+	cmd.Flags().StringVar(&noiseMix, "noise-mix", "small=4,medium=2,large=1", "Noise Deployments per namespace by size class (small=100m/128Mi, medium=250m/256Mi, large=500m/512Mi)")
+	cmd.Flags().StringVar(&outputPath, "out", "", "Write JSON output to a file (default: results/baseline.json or results/descheduler.json)")
+}
 
+func init() {
+	addRunFlags(benchmarkCmd)
+	benchmarkCmd.Flags().StringVar(&traceOptions.Endpoint, "otlp-endpoint", "", "Export run traces to this OTLP/HTTP endpoint (e.g. http://localhost:4318)")
+	benchmarkCmd.Flags().StringVar(&outputFormat, "format", report.FormatJSON, "Comma-separated output formats; json is always written, csv and ndjson add tables in a directory named after --out")
+	benchmarkCmd.Flags().StringVar(&storeDir, "store", store.DefaultDir, "Archive results in this store for history and regress (empty disables)")
+	benchmarkCmd.Flags().StringArrayVar(&assertExprs, "assert", nil, "Fail with exit code 3 unless the summary satisfies this expression, e.g. after.pods_stddev<=1.0 (repeatable)")
+	benchmarkCmd.Flags().StringVar(&junitPath, "junit", "", "Write a JUnit XML report of the --assert results to this file")
+	benchmarkCmd.Flags().StringVar(&grafanaURL, "grafana-url", "", "Post step annotations to this Grafana (e.g. http://localhost:3000)")
+	benchmarkCmd.Flags().StringVar(&grafanaToken, "grafana-token", "", "Grafana service account token (default: $GRAFANA_TOKEN)")
+	benchmarkCmd.Flags().StringVar(&prometheusURL, "prometheus-url", "", "Enrich results with range queries against this Prometheus (e.g. http://localhost:9090)")
+	benchmarkCmd.Flags().StringVar(&prometheusQueries, "prometheus-queries", "", "YAML list of {name, expr} queries replacing the defaults")
+	benchmarkCmd.Flags().DurationVar(&prometheusStep, "prometheus-step", 15*time.Second, "Range query resolution")
+	benchmarkCmd.Flags().StringVar(&traceOptions.File, "trace-file", "", "Write run traces as JSON spans to this file")
 	rootCmd.AddCommand(benchmarkCmd)
 }
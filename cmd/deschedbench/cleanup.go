@@ -13,12 +13,14 @@ import (
 )
 
 var (
-	cleanupForce bool
+	cleanupForce  bool
+	cleanupDryRun bool
+	cleanupAll    bool
 )
 
 var cleanupCmd = &cobra.Command{
 	Use:   "cleanup",
-	Short: "Delete namespaces created by deschedbench",
+	Short: "Delete namespaces and cluster-scoped objects created by deschedbench",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 0 {
 			return fmt.Errorf("cleanup does not accept positional arguments")
@@ -32,14 +34,32 @@ var cleanupCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 		defer cancel()
 		cleanupSvc := cleanup.NewCleanupService(client, logging.GetLogger())
-		return cleanupSvc.Run(ctx, cleanup.Scope{
+		scope := cleanup.Scope{
 			NamespacePrefix: "deschedbench-",
 			Wait:            !cleanupForce,
-		})
+			AllNodes:        cleanupAll,
+		}
+		if cleanupDryRun {
+			actions, err := cleanupSvc.Plan(ctx, scope)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if len(actions) == 0 {
+				fmt.Fprintln(out, "nothing to clean up")
+			}
+			for _, action := range actions {
+				fmt.Fprintln(out, action)
+			}
+			return nil
+		}
+		return cleanupSvc.Run(ctx, scope)
 	},
 }
 
 func init() {
 	cleanupCmd.Flags().BoolVar(&cleanupForce, "force", false, "Skip waiting for namespace deletion")
+	cleanupCmd.Flags().BoolVar(&cleanupDryRun, "dry-run", false, "List what would be deleted or uncordoned without changing anything")
+	cleanupCmd.Flags().BoolVar(&cleanupAll, "all", false, "Uncordon every unschedulable node, not only nodes cordoned by deschedbench")
 	rootCmd.AddCommand(cleanupCmd)
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"k8s-descheduler-benchmark/internal/report"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportDir    string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export RESULT.json [RESULT.json...]",
+	Short: "Write CSV or NDJSON tables from result files",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formats, err := report.ParseFormats(exportFormat)
+		if err != nil {
+			return err
+		}
+		if len(formats) == 0 {
+			return fmt.Errorf("--format must include csv or ndjson")
+		}
+		if exportDir != "" && len(args) > 1 {
+			return fmt.Errorf("--dir takes a single result file; without it each file exports next to itself")
+		}
+		out := cmd.OutOrStdout()
+		for _, path := range args {
+			result, err := report.LoadResult(path)
+			if err != nil {
+				return err
+			}
+			dir := exportDir
+			if dir == "" {
+				dir = report.ExportDir(path)
+			}
+			for _, format := range formats {
+				paths, err := report.Export(dir, result, format)
+				if err != nil {
+					return err
+				}
+				for _, p := range paths {
+					fmt.Fprintln(out, p)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", report.FormatCSV, "Comma-separated export formats (csv, ndjson)")
+	exportCmd.Flags().StringVar(&exportDir, "dir", "", "Output directory (default: the result path without .json)")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"k8s-descheduler-benchmark/internal/report"
+	"k8s-descheduler-benchmark/internal/store"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	storeDir      string
+	historyOutput string
+	historyFilter store.Filter
+	historyLimit  int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List archived benchmark runs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if historyOutput != "text" && historyOutput != "json" {
+			return fmt.Errorf("--output must be text or json, got %q", historyOutput)
+		}
+		entries, err := store.New(storeDir).List(historyFilter)
+		if err != nil {
+			return err
+		}
+		if historyLimit > 0 && len(entries) > historyLimit {
+			entries = entries[len(entries)-historyLimit:]
+		}
+		if historyOutput == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(entries)
+		}
+		return store.WriteHistoryText(os.Stdout, entries)
+	},
+}
+
+var historyImportCmd = &cobra.Command{
+	Use:   "import RESULT.json [RESULT.json...]",
+	Short: "Add existing result files to the store",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s := store.New(storeDir)
+		for _, path := range args {
+			result, err := report.LoadResult(path)
+			if err != nil {
+				return err
+			}
+			entry, err := s.Add(result)
+			if errors.Is(err, store.ErrExists) {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s already stored\n", path, entry.RunID)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: stored as %s (config %s)\n", path, entry.RunID, entry.Config)
+		}
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.PersistentFlags().StringVar(&storeDir, "store", store.DefaultDir, "Results store directory")
+	historyCmd.Flags().StringVar(&historyOutput, "output", "text", "Output format (text, json)")
+	historyCmd.Flags().StringVar(&historyFilter.Profile, "profile", "", "Only runs with this profile")
+	historyCmd.Flags().StringVar(&historyFilter.Image, "image", "", "Only runs with this descheduler image")
+	historyCmd.Flags().StringVar(&historyFilter.Cluster, "cluster", "", "Only runs on this cluster fingerprint (prefix)")
+	historyCmd.Flags().StringVar(&historyFilter.Config, "config", "", "Only runs with this configuration key (prefix)")
+	historyCmd.Flags().StringVar(&historyFilter.Status, "status", "", "Only runs with this status (success, failed, cancelled, interrupted)")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 0, "Show only the most recent N runs")
+	historyCmd.AddCommand(historyImportCmd)
+	rootCmd.AddCommand(historyCmd)
+}
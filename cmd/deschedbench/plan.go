@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/logging"
+	benchsvc "k8s-descheduler-benchmark/internal/service/benchmark"
+
+	"github.com/spf13/cobra"
+)
+
+var planOutput string
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what a benchmark run would do without changing the cluster",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if planOutput != "text" && planOutput != "json" {
+			return fmt.Errorf("--output must be text or json, got %q", planOutput)
+		}
+		client, info, err := k8s.NewClient(clientQPS, clientBurst)
+		if err != nil {
+			return err
+		}
+
+		cfg := runConfig(info)
+		if len(deschedulerImages) > 0 {
+			cfg.DeschedulerImage = deschedulerImages[0]
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		runner := benchsvc.Runner{Client: client, Logger: logging.GetLogger()}
+		report, err := runner.DryRun(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if planOutput == "json" {
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		}
+		if len(deschedulerImages) > 1 {
+			fmt.Fprintf(out, "note: showing the first of %d descheduler images; each image runs the same plan\n\n", len(deschedulerImages))
+		}
+		return benchsvc.WriteDryRun(out, report)
+	},
+}
+
+func init() {
+	addRunFlags(planCmd)
+	planCmd.Flags().StringVar(&planOutput, "output", "text", "Output format (text, json)")
+	rootCmd.AddCommand(planCmd)
+}
@@ -2,32 +2,73 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
 
 	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/logging"
-	"k8s-descheduler-benchmark/internal/service/cleanup"
+	benchsvc "k8s-descheduler-benchmark/internal/service/benchmark"
+	"k8s-descheduler-benchmark/internal/service/preflight"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	preflightOutput     string
+	preflightIterations int
+)
+
 var preflightCmd = &cobra.Command{
 	Use:   "preflight",
 	Short: "Verify the cluster is ready for a benchmark run",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client, _, err := k8s.NewClient(clientQPS, clientBurst)
+		if preflightOutput != "text" && preflightOutput != "json" {
+			return fmt.Errorf("--output must be text or json, got %q", preflightOutput)
+		}
+		client, info, err := k8s.NewClient(clientQPS, clientBurst)
 		if err != nil {
 			return err
 		}
+		// The capacity check uses the pods the benchmark would plan, so
+		// --mix and --noise-* are accounted for as in a run.
+		plan, err := benchsvc.NewPlanBuilder().Build(runConfig(info))
+		if err != nil {
+			return err
+		}
+
+		svc := preflight.NewPreflightService(client, logging.GetLogger())
+		result := svc.Run(context.Background(), preflight.Options{
+			Mix:               plan.Mix,
+			SizeClasses:       plan.SizeClasses,
+			Noise:             plan.Noise,
+			DeschedulerImages: deschedulerImages,
+			Iterations:        preflightIterations,
+			MetricsPort:       metricsPort,
+		})
 
-		svc := cleanup.NewCleanupService(client, logging.GetLogger())
-		if err := svc.Preflight(context.Background()); err != nil {
+		if preflightOutput == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(result); err != nil {
+				return err
+			}
+		} else if err := preflight.WriteText(os.Stdout, result); err != nil {
 			return err
 		}
-		logging.GetLogger().Info("preflight ok")
+
+		if result.Failed() {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("preflight failed: %s", strings.Join(result.FailedChecks(), ", "))
+		}
 		return nil
 	},
 }
 
 func init() {
+	preflightCmd.Flags().StringVar(&preflightOutput, "output", "text", "Output format (text, json)")
+	addRunFlags(preflightCmd)
+	preflightCmd.Flags().IntVar(&preflightIterations, "iterations", 2, "Maintenance iterations the run will perform")
 	rootCmd.AddCommand(preflightCmd)
 }
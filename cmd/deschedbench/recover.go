@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/journal"
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/logging"
+	"k8s-descheduler-benchmark/internal/service/recovery"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	recoverJournalDir string
+	recoverRunID      string
+	recoverDryRun     bool
+	recoverForce      bool
+)
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Restore the cluster after an interrupted run and write its partial results",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("recover does not accept positional arguments")
+		}
+
+		client, _, err := k8s.NewClient(clientQPS, clientBurst)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		svc := recovery.NewRecoveryService(client, logging.GetLogger())
+		outcomes, err := svc.Run(ctx, recovery.Options{
+			Dir:    recoverJournalDir,
+			RunID:  recoverRunID,
+			Wait:   !recoverForce,
+			DryRun: recoverDryRun,
+		})
+		out := cmd.OutOrStdout()
+		if len(outcomes) == 0 && err == nil {
+			fmt.Fprintln(out, "no interrupted runs")
+		}
+		for _, outcome := range outcomes {
+			fmt.Fprintf(out, "run %s (namespace %s, last phase %s)\n", outcome.RunID, outcome.Namespace, outcome.Phase)
+			for _, action := range outcome.Actions {
+				fmt.Fprintf(out, "  %s\n", action)
+			}
+			for _, warning := range outcome.Warnings {
+				fmt.Fprintf(out, "  warning: %s\n", warning)
+			}
+			if outcome.PartialPath != "" {
+				fmt.Fprintf(out, "  partial results: %s\n", outcome.PartialPath)
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	recoverCmd.Flags().StringVar(&recoverJournalDir, "journal-dir", journal.DefaultDir, "Directory holding run journals")
+	recoverCmd.Flags().StringVar(&recoverRunID, "run-id", "", "Recover only this run, even if its journal is not marked running")
+	recoverCmd.Flags().BoolVar(&recoverDryRun, "dry-run", false, "List what would be restored without changing anything")
+	recoverCmd.Flags().BoolVar(&recoverForce, "force", false, "Skip waiting for namespace deletion")
+	rootCmd.AddCommand(recoverCmd)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"k8s-descheduler-benchmark/internal/report"
+	"k8s-descheduler-benchmark/internal/store"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	regressRunID   string
+	regressOutput  string
+	regressFilter  store.Filter
+	regressOptions = store.DefaultRegressOptions()
+)
+
+var regressCmd = &cobra.Command{
+	Use:   "regress",
+	Short: "Compare the latest run against the history of the same configuration",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if regressOutput != "text" && regressOutput != "json" {
+			return fmt.Errorf("--output must be text or json, got %q", regressOutput)
+		}
+		cmd.SilenceUsage = true
+		entries, err := store.New(storeDir).List(store.Filter{})
+		if err != nil {
+			return err
+		}
+
+		var latest *store.Entry
+		for i := range entries {
+			e := entries[i]
+			if regressRunID != "" {
+				if e.RunID == regressRunID {
+					latest = &entries[i]
+				}
+				continue
+			}
+			if e.Status == report.StatusSuccess && regressFilter.Match(e) {
+				latest = &entries[i]
+			}
+		}
+		if latest == nil {
+			return fmt.Errorf("no matching run in %s", storeDir)
+		}
+
+		history := store.Baseline(entries, *latest, regressOptions.Window)
+		if len(history) == 0 {
+			return fmt.Errorf("no previous successful runs with config %s", latest.Config)
+		}
+		result := store.Regress(*latest, history, regressOptions)
+		if regressOutput == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(result); err != nil {
+				return err
+			}
+		} else if err := store.WriteRegressionText(os.Stdout, result); err != nil {
+			return err
+		}
+		if result.Regressed() {
+			return fmt.Errorf("run %s: %w", latest.RunID, store.ErrRegression)
+		}
+		return nil
+	},
+}
+
+func init() {
+	regressCmd.Flags().StringVar(&storeDir, "store", store.DefaultDir, "Results store directory")
+	regressCmd.Flags().StringVar(&regressRunID, "run", "", "Run ID to check (default: the latest successful run)")
+	regressCmd.Flags().StringVar(&regressFilter.Profile, "profile", "", "Pick the latest run with this profile")
+	regressCmd.Flags().StringVar(&regressFilter.Image, "image", "", "Pick the latest run with this descheduler image")
+	regressCmd.Flags().StringVar(&regressOutput, "output", "text", "Output format (text, json)")
+	regressCmd.Flags().IntVar(&regressOptions.Window, "window", regressOptions.Window, "Number of previous runs to compare against")
+	regressCmd.Flags().IntVar(&regressOptions.MinHistory, "min-history", regressOptions.MinHistory, "Fewest previous runs a metric needs to be judged")
+	regressCmd.Flags().Float64Var(&regressOptions.ZScore, "z", regressOptions.ZScore, "Standard deviations above the mean that count as significant")
+	regressCmd.Flags().Float64Var(&regressOptions.MinChange, "min-change", regressOptions.MinChange, "Smallest relative increase over the mean that counts (0.1 = 10%)")
+	rootCmd.AddCommand(regressCmd)
+}
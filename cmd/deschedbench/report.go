@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"k8s-descheduler-benchmark/internal/report"
+
+	"github.com/spf13/cobra"
+)
+
+var reportHTML string
+
+var reportCmd = &cobra.Command{
+	Use:   "report RESULT.json [RESULT.json...]",
+	Short: "Render result files as a self-contained HTML report",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if reportHTML == "" {
+			return fmt.Errorf("--html is required")
+		}
+		runs := make([]report.HTMLRun, 0, len(args))
+		for _, path := range args {
+			result, err := report.LoadResult(path)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, report.HTMLRun{Source: path, Result: result})
+		}
+		file, err := os.Create(reportHTML)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := report.WriteHTML(file, runs); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), reportHTML)
+		return nil
+	},
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportHTML, "html", "", "Write the HTML report to this file")
+	rootCmd.AddCommand(reportCmd)
+}
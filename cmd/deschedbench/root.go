@@ -1,25 +1,37 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"k8s-descheduler-benchmark/internal/logging"
+	benchsvc "k8s-descheduler-benchmark/internal/service/benchmark"
+	"k8s-descheduler-benchmark/internal/store"
 
 	"github.com/spf13/cobra"
 )
 
+// Process exit codes. CI can tell a run that broke from a run whose
+// --assert thresholds did not hold or that regressed against history.
+const (
+	exitError           = 1
+	exitAssertionFailed = 3
+	exitRegression      = 4
+)
+
 var (
 	clientQPS   float32
 	clientBurst int
 	metricsPort int
+	logOptions  logging.Options
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "deschedbench",
 	Short: "Descheduler performance & impact benchmark tool",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		logging.InitLogger()
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return logging.Configure(logOptions)
 	},
 }
 
@@ -27,11 +39,22 @@ func init() {
 	rootCmd.PersistentFlags().Float32Var(&clientQPS, "client-qps", 200, "Kubernetes client QPS")
 	rootCmd.PersistentFlags().IntVar(&clientBurst, "client-burst", 400, "Kubernetes client burst")
 	rootCmd.PersistentFlags().IntVar(&metricsPort, "metrics-port", 8080, "Port for Prometheus metrics")
+	rootCmd.PersistentFlags().StringVar(&logOptions.Format, "log-format", logging.FormatText, "Log format: text or json")
+	rootCmd.PersistentFlags().StringVar(&logOptions.Level, "log-level", "info", "Log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logOptions.File, "log-file", "", "Also append logs to this file")
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	err := rootCmd.Execute()
+	_ = logging.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		switch {
+		case errors.Is(err, benchsvc.ErrAssertionsFailed):
+			os.Exit(exitAssertionFailed)
+		case errors.Is(err, store.ErrRegression):
+			os.Exit(exitRegression)
+		}
+		os.Exit(exitError)
 	}
 }
@@ -3,30 +3,64 @@ package benchmark
 import (
 	"time"
 
+	"k8s-descheduler-benchmark/internal/descheduler"
 	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/workloads"
 )
 
 type MaintenanceConfig struct {
-	RunID             string
-	Namespace         string
-	WorkloadImage     string
-	WorkloadMix       workloads.Mix
-	SizeClasses       map[string]workloads.SizeClass
-	LabelSelector     string
-	Labels            map[string]string
-	RecordPhase       func(name string) error
-	WaitTimeout       time.Duration
-	PostUncordonWait  time.Duration
-	DrainIterations   int
-	DeschedulerImage  string
-	DeschedulerNS     string
-	DeschedulerPolicy string
-	DeschedulerCron   string
+	RunID               string
+	Profile             string
+	Namespace           string
+	WorkloadImage       string
+	WorkloadMix         workloads.Mix
+	SizeClasses         map[string]workloads.SizeClass
+	LabelSelector       string
+	Labels              map[string]string
+	RecordPhase         func(name string) error
+	RecordCordon        func(node string, cordoned bool) error
+	RecordStep          func(step StepRecord)
+	WaitTimeout         time.Duration
+	PostUncordonWait    time.Duration
+	DrainIterations     int
+	DeschedulerImage    string
+	DeschedulerNS       string
+	DeschedulerPolicy   string
+	DeschedulerMode     string
+	DeschedulerCron     string
+	DeschedulerInterval time.Duration
+	// Noise are background workloads created in their own namespaces
+	// before the benchmark pods. Drains and the descheduler leave them be.
+	Noise []workloads.WorkloadConfig
+	// PriorityClasses maps the priority tiers of WorkloadMix to the
+	// PriorityClass names created for this run.
+	PriorityClasses map[string]string
+}
+
+// StepRecord describes a finished step of the scenario. Iteration and
+// Node are zero for setup steps.
+type StepRecord struct {
+	Name      string
+	Iteration int
+	Node      string
+	Start     time.Time
+	End       time.Time
+	Err       error
 }
 
 type ScenarioResult struct {
-	Evictions []k8s.EvictionRecord
-	Duration  time.Duration
-	DrainNode string
+	Evictions           []k8s.EvictionRecord
+	DeschedulerActivity []descheduler.Activity
+	DeschedulerImageID  string
+	Duration            time.Duration
+	DrainNode           string
+	// LastPhase is the last phase marked; on failure it is the phase that
+	// was in progress.
+	LastPhase string
+	// PodLatencies has the startup breakdown of the initial pods and of
+	// every replacement created after a drain or a descheduler run.
+	PodLatencies []k8s.PodLatency
+	// Disruptions lists the benchmark pods evicted or preempted during
+	// drains and descheduler passes, with their priority tier.
+	Disruptions []k8s.Disruption
 }
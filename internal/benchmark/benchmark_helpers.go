@@ -57,7 +57,7 @@ func countSchedulableWorkers(nodes []corev1.Node) int {
 		if node.Spec.Unschedulable {
 			continue
 		}
-		if isControlPlaneNode(node.Labels) {
+		if k8s.IsControlPlaneNode(node.Labels) {
 			continue
 		}
 		count++
@@ -73,7 +73,7 @@ func pickDrainNode(nodes []corev1.Node) (string, error) {
 		if node.Spec.Unschedulable {
 			continue
 		}
-		if isControlPlaneNode(node.Labels) {
+		if k8s.IsControlPlaneNode(node.Labels) {
 			continue
 		}
 		return node.Name, nil
@@ -87,7 +87,7 @@ func pickDrainNodeExcluding(nodes []corev1.Node, excluded map[string]struct{}) (
 		if node.Spec.Unschedulable {
 			continue
 		}
-		if isControlPlaneNode(node.Labels) {
+		if k8s.IsControlPlaneNode(node.Labels) {
 			continue
 		}
 		if _, ok := excluded[node.Name]; ok {
@@ -123,16 +123,3 @@ func attrsToArgs(attrs []slog.Attr) []any {
 	}
 	return args
 }
-
-func isControlPlaneNode(labels map[string]string) bool {
-	if labels == nil {
-		return false
-	}
-	if _, ok := labels["node-role.kubernetes.io/control-plane"]; ok {
-		return true
-	}
-	if _, ok := labels["node-role.kubernetes.io/master"]; ok {
-		return true
-	}
-	return false
-}
@@ -2,98 +2,217 @@ package benchmark
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 	"time"
 
+	"k8s-descheduler-benchmark/internal/descheduler"
+	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/logging"
+	"k8s-descheduler-benchmark/internal/tracing"
 	"k8s-descheduler-benchmark/internal/workloads"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/kubernetes"
 )
 
 type maintenanceRunner struct {
-	ctx            context.Context
-	client         kubernetes.Interface
-	cfg            MaintenanceConfig
-	logger         *slog.Logger
-	workloadName   string
-	totalPods      int32
-	drainNode      string
-	preEvictLabels map[string]string
-	drainedNodes   map[string]struct{}
-	iteration      int
+	ctx              context.Context
+	client           kubernetes.Interface
+	cfg              MaintenanceConfig
+	logger           *slog.Logger
+	baseLogger       *slog.Logger
+	workloadName     string
+	totalPods        int32
+	drainNode        string
+	preEvictLabels   map[string]string
+	preEvictKinds    map[string]string
+	priorities       map[string]string
+	disruptions      []k8s.Disruption
+	drainedNodes     map[string]struct{}
+	iteration        int
+	drainStart       time.Time
+	deschedulerStart time.Time
+	latencies        map[string]k8s.PodLatency
+	activity         []descheduler.Activity
+	// lastMetrics is the previous raw scrape of each descheduler pod, by
+	// source.
+	lastMetrics map[string]descheduler.Metrics
+	imageID     string
+	lastPhase   string
+	// spanCtx parents step spans: the run span, or the iteration span
+	// while an iteration is running. span is the innermost open span.
+	spanCtx context.Context
+	span    trace.Span
 }
 
+// RunMaintenance runs the maintenance scenario. The result is returned even
+// when err is non-nil and holds everything collected up to the failure.
 func RunMaintenance(ctx context.Context, client kubernetes.Interface, cfg MaintenanceConfig) (ScenarioResult, error) {
 	start := time.Now()
 	runner := newMaintenanceRunner(ctx, client, cfg)
+	err := runner.run()
+	return runner.result(start), err
+}
 
-	if err := runner.prepareWorkloads(); err != nil {
-		return ScenarioResult{}, err
+func (m *maintenanceRunner) run() error {
+	if err := m.validateWindow(); err != nil {
+		return err
+	}
+	iterations := m.cfg.DrainIterations
+	if iterations <= 0 {
+		iterations = 1
 	}
-	if err := runner.snapshotBefore(); err != nil {
-		return ScenarioResult{}, err
+	if len(m.cfg.Noise) > 0 {
+		// Noise goes first so the drain plan counts its requests.
+		if err := m.step("noise", m.prepareNoise); err != nil {
+			return err
+		}
 	}
-	if err := runner.ensureDescheduler(); err != nil {
-		return ScenarioResult{}, err
+	if err := m.planDrains(iterations); err != nil {
+		return err
 	}
-	if err := runner.capturePreEvictions(); err != nil {
-		return ScenarioResult{}, err
+	if err := m.step("workload", m.prepareWorkloads); err != nil {
+		return err
 	}
-
-	iterations := cfg.DrainIterations
-	if iterations <= 0 {
-		iterations = 1
+	if err := m.snapshotBefore(); err != nil {
+		return err
+	}
+	if m.cfg.DeschedulerPolicy != "" {
+		if err := m.step("descheduler:install", m.ensureDescheduler); err != nil {
+			return err
+		}
+	}
+	if err := m.capturePreEvictions(); err != nil {
+		return err
 	}
-	if err := runner.validateIterations(iterations); err != nil {
-		return ScenarioResult{}, err
+
+	if err := m.validateIterations(iterations); err != nil {
+		return err
 	}
 
 	for i := 0; i < iterations; i++ {
-		runner.iteration = i + 1
-		if err := runner.mark("maintenance:iteration", logging.StringField("iteration", fmt.Sprintf("%d", runner.iteration))); err != nil {
-			return ScenarioResult{}, err
-		}
-		if err := runner.selectDrainNode(); err != nil {
-			return ScenarioResult{}, err
-		}
-		if err := runner.cordonAndDrain(); err != nil {
-			return ScenarioResult{}, err
-		}
-		if err := runner.waitForReschedule(); err != nil {
-			return ScenarioResult{}, err
-		}
-		if err := runner.uncordon(); err != nil {
-			return ScenarioResult{}, err
-		}
-		if err := runner.runDescheduler(); err != nil {
-			return ScenarioResult{}, err
-		}
-		if err := runner.waitPostUncordon(); err != nil {
-			return ScenarioResult{}, err
+		m.iteration = i + 1
+		m.logger = m.baseLogger.With(slog.Int("iteration", m.iteration))
+		if err := m.runIteration(); err != nil {
+			return err
 		}
 	}
-	if err := runner.snapshotAfter(); err != nil {
-		return ScenarioResult{}, err
+	return m.snapshotAfter()
+}
+
+// runIteration drains one node and lets the descheduler react, inside an
+// iteration span that parents one span per step.
+func (m *maintenanceRunner) runIteration() (err error) {
+	ctx, span := tracing.Tracer().Start(m.ctx, "iteration",
+		trace.WithAttributes(attribute.Int("iteration", m.iteration)),
+	)
+	m.spanCtx = ctx
+	defer func() {
+		span.SetAttributes(attribute.String("node", m.drainNode))
+		tracing.End(span, err)
+		m.spanCtx = m.ctx
+	}()
+
+	if err := m.mark("maintenance:iteration"); err != nil {
+		return err
+	}
+	if err := m.selectDrainNode(); err != nil {
+		return err
+	}
+	if err := m.step("cordon", m.cordon); err != nil {
+		return err
+	}
+	if err := m.step("drain", m.drain); err != nil {
+		return err
 	}
+	if err := m.step("reschedule", m.waitForReschedule); err != nil {
+		return err
+	}
+	if err := m.step("uncordon", m.uncordon); err != nil {
+		return err
+	}
+	if m.cfg.DeschedulerPolicy == "" {
+		return m.waitPostUncordon()
+	}
+	if err := m.step("descheduler", m.runDescheduler); err != nil {
+		return err
+	}
+	// A long-running descheduler already waited inside its step, which
+	// covers the window its activity is measured over.
+	if m.deschedulerMode() == descheduler.ModeJob {
+		if err := m.waitPostUncordon(); err != nil {
+			return err
+		}
+	}
+	m.recordLatencies(m.ctx, k8s.TriggerDescheduler, m.deschedulerStart)
+	return nil
+}
 
-	evictions := runner.collectEvictions()
+// step runs fn inside a span named after the step and reports the step
+// through RecordStep. Steps add their own attributes through m.span.
+func (m *maintenanceRunner) step(name string, fn func() error) error {
+	_, span := tracing.Tracer().Start(m.spanCtx, name)
+	m.span = span
+	start := time.Now()
+	err := fn()
+	tracing.End(span, err)
+	m.span = trace.SpanFromContext(m.spanCtx)
+	if m.cfg.RecordStep != nil {
+		record := StepRecord{Name: name, Start: start, End: time.Now(), Err: err}
+		if m.iteration > 0 {
+			record.Iteration = m.iteration
+			record.Node = m.drainNode
+		}
+		m.cfg.RecordStep(record)
+	}
+	return err
+}
 
+// result assembles what the run collected. After a cancellation the run
+// context is done, so evictions are collected with a short-lived context.
+func (m *maintenanceRunner) result(start time.Time) ScenarioResult {
+	ctx := m.ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+	}
+	var evictions []k8s.EvictionRecord
+	if m.preEvictLabels != nil {
+		evictions = m.collectEvictions(ctx)
+	}
+	m.refreshLatencies(ctx)
 	return ScenarioResult{
-		Evictions: evictions,
-		Duration:  time.Since(start),
-		DrainNode: runner.drainNode,
-	}, nil
+		Evictions:           evictions,
+		PodLatencies:        m.podLatencies(),
+		DeschedulerActivity: m.activity,
+		DeschedulerImageID:  m.imageID,
+		Duration:            time.Since(start),
+		DrainNode:           m.drainNode,
+		LastPhase:           m.lastPhase,
+		Disruptions:         m.disruptions,
+	}
 }
 
 func newMaintenanceRunner(ctx context.Context, client kubernetes.Interface, cfg MaintenanceConfig) *maintenanceRunner {
+	logger := logging.GetLogger().With(
+		logging.StringField("run_id", cfg.RunID),
+		logging.StringField("profile", cfg.Profile),
+	)
 	return &maintenanceRunner{
 		ctx:          ctx,
 		client:       client,
 		cfg:          cfg,
-		logger:       logging.GetLogger(),
+		logger:       logger,
+		baseLogger:   logger,
 		workloadName: "deschedbench",
 		totalPods:    workloads.MixTotal(cfg.WorkloadMix),
 		drainedNodes: map[string]struct{}{},
+		latencies:    map[string]k8s.PodLatency{},
+		priorities:   map[string]string{},
+		lastMetrics:  map[string]descheduler.Metrics{},
+		spanCtx:      ctx,
+		span:         trace.SpanFromContext(ctx),
 	}
 }
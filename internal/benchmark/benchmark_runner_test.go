@@ -2,9 +2,23 @@ package benchmark
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"k8s-descheduler-benchmark/internal/descheduler"
+	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/workloads"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestNewMaintenanceRunnerDefaults(t *testing.T) {
@@ -19,3 +33,171 @@ func TestNewMaintenanceRunnerDefaults(t *testing.T) {
 		t.Fatalf("unexpected total pods: %d", runner.totalPods)
 	}
 }
+
+func TestRunMaintenanceReturnsPartialResultOnFailure(t *testing.T) {
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			}},
+		}
+	}
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	client := fake.NewSimpleClientset(node("w1"), node("w2"), node("w3"))
+	result, err := RunMaintenance(context.Background(), client, MaintenanceConfig{
+		RunID:           "run-a",
+		Namespace:       "deschedbench-run-a",
+		WorkloadMix:     workloads.Mix{"small": 4},
+		SizeClasses:     map[string]workloads.SizeClass{"small": {Name: "small", CPU: "100m", Memory: "128Mi"}},
+		WaitTimeout:     10 * time.Millisecond,
+		DrainIterations: 1,
+	})
+	if err == nil {
+		t.Fatalf("expected workloads to never become ready on a fake cluster")
+	}
+	if result.LastPhase != "workload:create" {
+		t.Fatalf("expected failure during workload:create, got %q", result.LastPhase)
+	}
+	if result.Duration <= 0 {
+		t.Fatalf("expected duration recorded for failed run")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "workload" || spans[0].Status().Code != codes.Error {
+		t.Fatalf("expected one failed workload span, got %d spans", len(spans))
+	}
+}
+
+func TestRunMaintenanceCreatesNoiseFirst(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "w1"}})
+	noise := workloads.WorkloadConfig{
+		Namespace:   "deschedbench-run-a-noise-1",
+		NamePrefix:  "noise",
+		Labels:      map[string]string{"deschedbench-noise": "true"},
+		Mix:         workloads.Mix{"large": 2},
+		SizeClasses: workloads.DefaultSizeClasses(),
+		PodImage:    "registry.k8s.io/pause:3.9",
+	}
+	result, err := RunMaintenance(context.Background(), client, MaintenanceConfig{
+		RunID:           "run-a",
+		Namespace:       "deschedbench-run-a",
+		WorkloadMix:     workloads.Mix{"small": 4},
+		SizeClasses:     map[string]workloads.SizeClass{"small": {Name: "small", CPU: "100m", Memory: "128Mi"}},
+		WaitTimeout:     10 * time.Millisecond,
+		DrainIterations: 1,
+		Noise:           []workloads.WorkloadConfig{noise},
+	})
+	if err == nil {
+		t.Fatalf("expected noise workloads to never become ready on a fake cluster")
+	}
+	if result.LastPhase != "noise:create" {
+		t.Fatalf("expected failure during noise:create, got %q", result.LastPhase)
+	}
+	ns, err := client.CoreV1().Namespaces().Get(context.Background(), noise.Namespace, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected noise namespace: %v", err)
+	}
+	if k8s.RunIDOf(ns) != "run-a" {
+		t.Fatalf("expected noise namespace owned by the run, got %q", k8s.RunIDOf(ns))
+	}
+	dep, err := client.AppsV1().Deployments(noise.Namespace).Get(context.Background(), "noise-large", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected noise deployment: %v", err)
+	}
+	if _, ok := dep.Spec.Template.Labels["deschedbench"]; ok {
+		t.Fatalf("noise pods must not carry the label the policies select on")
+	}
+	if _, err := client.CoreV1().Namespaces().Get(context.Background(), "deschedbench-run-a", metav1.GetOptions{}); err == nil {
+		t.Fatalf("benchmark namespace must not be created before noise is ready")
+	}
+}
+
+func TestRescheduleTimeoutReportsReschedulePhase(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	var phases []string
+	runner := newMaintenanceRunner(context.Background(), client, MaintenanceConfig{
+		RunID:         "run-a",
+		Namespace:     "deschedbench-run-a",
+		WorkloadMix:   workloads.Mix{"small": 4},
+		LabelSelector: "deschedbench=true",
+		WaitTimeout:   10 * time.Millisecond,
+		RecordPhase: func(name string) error {
+			phases = append(phases, name)
+			return nil
+		},
+	})
+	runner.iteration = 1
+	if err := runner.mark("drain:done"); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+	if err := runner.step("reschedule", runner.waitForReschedule); err == nil {
+		t.Fatalf("expected the reschedule wait to time out")
+	}
+	if got := runner.result(time.Now()).LastPhase; got != "reschedule:start" {
+		t.Fatalf("expected failure during reschedule:start, got %q (phases %v)", got, phases)
+	}
+}
+
+func TestLongRunningDeschedulerStepSpansObservation(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	var steps []StepRecord
+	runner := newMaintenanceRunner(context.Background(), client, MaintenanceConfig{
+		RunID:             "run-a",
+		Namespace:         "deschedbench-run-a",
+		DeschedulerNS:     "deschedbench-run-a",
+		DeschedulerPolicy: "apiVersion: descheduler/v1alpha2\nkind: DeschedulerPolicy\n",
+		DeschedulerMode:   descheduler.ModeDeployment,
+		PostUncordonWait:  50 * time.Millisecond,
+		RecordStep:        func(step StepRecord) { steps = append(steps, step) },
+	})
+	runner.iteration = 1
+	if err := runner.step("descheduler", runner.runDescheduler); err != nil {
+		t.Fatalf("descheduler step failed: %v", err)
+	}
+	if len(steps) != 1 || steps[0].End.Sub(steps[0].Start) < 50*time.Millisecond {
+		t.Fatalf("expected the descheduler step to span the post-uncordon wait, got %+v", steps)
+	}
+	if len(runner.activity) != 1 {
+		t.Fatalf("expected the activity observed inside the step, got %d entries", len(runner.activity))
+	}
+	// No descheduler pod ever ran, so the activity says why it has no metrics.
+	if activity := runner.activity[0]; activity.Metrics != nil || !strings.Contains(activity.MetricsWarning, "metrics unavailable") {
+		t.Fatalf("expected missing metrics recorded on the activity, got %+v", activity)
+	}
+}
+
+func TestWaitPostUncordonStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := newMaintenanceRunner(ctx, nil, MaintenanceConfig{PostUncordonWait: time.Hour})
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	if err := runner.waitPostUncordon(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the wait to return the cancellation, got %v", err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Fatalf("expected the wait to stop on cancel, waited %s", waited)
+	}
+}
+
+func TestRunMaintenanceRejectsEmptyObservationWindow(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	_, err := RunMaintenance(context.Background(), client, MaintenanceConfig{
+		RunID:             "run-a",
+		Namespace:         "deschedbench-run-a",
+		WorkloadMix:       workloads.Mix{"small": 4},
+		DeschedulerPolicy: "apiVersion: descheduler/v1alpha2\nkind: DeschedulerPolicy\n",
+		DeschedulerMode:   descheduler.ModeCronJob,
+	})
+	if err == nil || !strings.Contains(err.Error(), "post-uncordon wait") {
+		t.Fatalf("expected an empty window to be rejected, got %v", err)
+	}
+	if len(client.Actions()) != 0 {
+		t.Fatalf("expected nothing to be created, got %d actions", len(client.Actions()))
+	}
+}
@@ -1,21 +1,29 @@
 package benchmark
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"k8s-descheduler-benchmark/internal/capacity"
 	"k8s-descheduler-benchmark/internal/descheduler"
 	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/logging"
 	"k8s-descheduler-benchmark/internal/report"
 	"k8s-descheduler-benchmark/internal/workloads"
 
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 func (m *maintenanceRunner) mark(name string, attrs ...slog.Attr) error {
+	m.lastPhase = name
 	m.logger.Info(formatPhaseMessage(name), attrsToArgs(attrs)...)
 	if m.cfg.RecordPhase != nil {
 		if err := m.cfg.RecordPhase(name); err != nil {
@@ -25,8 +33,82 @@ func (m *maintenanceRunner) mark(name string, attrs ...slog.Attr) error {
 	return nil
 }
 
+// recordCordon reports a cordon before it happens and a restore after it
+// happened, so the run journal never misses a cordoned node.
+func (m *maintenanceRunner) recordCordon(cordoned bool) error {
+	if m.cfg.RecordCordon == nil {
+		return nil
+	}
+	return m.cfg.RecordCordon(m.drainNode, cordoned)
+}
+
+// planDrains fails fast when the workers left after any planned drain cannot
+// hold every benchmark pod, instead of timing out in waitForReschedule.
+func (m *maintenanceRunner) planDrains(iterations int) error {
+	shapes, err := capacity.Shapes(m.cfg.WorkloadMix, m.cfg.SizeClasses)
+	if err != nil {
+		return err
+	}
+	nodes, err := k8s.ListNodes(m.ctx, m.client, "")
+	if err != nil {
+		return err
+	}
+	pods, err := k8s.ListPods(m.ctx, m.client, "", "")
+	if err != nil {
+		return err
+	}
+	free := capacity.FreeNodes(nodes, pods, m.cfg.Namespace)
+	plan := capacity.PlanDrains(free, capacity.DrainOrder(free, iterations, 1), shapes)
+	if !plan.Feasible {
+		return fmt.Errorf("drain plan infeasible for %d pods:\n%s", plan.PodsTotal, capacity.Format(plan))
+	}
+	m.logger.Info("drain plan feasible",
+		logging.StringField("pods", fmt.Sprintf("%d", plan.PodsTotal)),
+		logging.StringField("iterations", fmt.Sprintf("%d", len(plan.Checks))),
+	)
+	return nil
+}
+
+// prepareNoise creates the noise namespaces and their Deployments and waits
+// until every noise pod is ready.
+func (m *maintenanceRunner) prepareNoise() error {
+	var pods int32
+	for _, noise := range m.cfg.Noise {
+		pods += workloads.MixTotal(noise.Mix)
+	}
+	m.span.SetAttributes(
+		attribute.Int("namespaces", len(m.cfg.Noise)),
+		attribute.Int("pods", int(pods)),
+	)
+	if err := m.mark("noise:create",
+		logging.StringField("namespaces", fmt.Sprintf("%d", len(m.cfg.Noise))),
+		logging.StringField("pods", fmt.Sprintf("%d", pods)),
+	); err != nil {
+		return err
+	}
+	for _, noise := range m.cfg.Noise {
+		if err := k8s.EnsureNamespace(m.ctx, m.client, noise.Namespace, m.cfg.RunID); err != nil {
+			return err
+		}
+		if err := workloads.EnsureWorkloads(m.ctx, m.client, noise); err != nil {
+			return err
+		}
+	}
+	for _, noise := range m.cfg.Noise {
+		if err := workloads.WaitForWorkloadsReady(m.ctx, m.client, noise.Namespace, noise.NamePrefix, noise.Mix, m.cfg.WaitTimeout); err != nil {
+			logSchedulingSummary(m.ctx, m.client, noise.Namespace, "", m.logger)
+			return fmt.Errorf("noise workloads in %s: %w", noise.Namespace, err)
+		}
+	}
+	return m.mark("noise:ready",
+		logging.StringField("namespaces", fmt.Sprintf("%d", len(m.cfg.Noise))),
+		logging.StringField("pods", fmt.Sprintf("%d", pods)),
+	)
+}
+
 func (m *maintenanceRunner) prepareWorkloads() error {
-	if err := k8s.EnsureNamespace(m.ctx, m.client, m.cfg.Namespace); err != nil {
+	m.span.SetAttributes(attribute.Int("pods", int(m.totalPods)))
+	if err := k8s.EnsureNamespace(m.ctx, m.client, m.cfg.Namespace, m.cfg.RunID); err != nil {
 		return err
 	}
 	if err := m.mark("workload:create",
@@ -35,14 +117,19 @@ func (m *maintenanceRunner) prepareWorkloads() error {
 	); err != nil {
 		return err
 	}
+	if err := m.ensurePriorityClasses(); err != nil {
+		return err
+	}
 	if err := workloads.EnsureWorkloads(m.ctx, m.client, workloads.WorkloadConfig{
-		Namespace:   m.cfg.Namespace,
-		NamePrefix:  m.workloadName,
-		Labels:      m.cfg.Labels,
-		Mix:         m.cfg.WorkloadMix,
-		SizeClasses: m.cfg.SizeClasses,
-		PodImage:    m.cfg.WorkloadImage,
-		PodLabels:   m.cfg.Labels,
+		Namespace:       m.cfg.Namespace,
+		NamePrefix:      m.workloadName,
+		Labels:          m.cfg.Labels,
+		Annotations:     map[string]string{k8s.RunIDAnnotation: m.cfg.RunID},
+		Mix:             m.cfg.WorkloadMix,
+		SizeClasses:     m.cfg.SizeClasses,
+		PodImage:        m.cfg.WorkloadImage,
+		PodLabels:       m.cfg.Labels,
+		PriorityClasses: m.cfg.PriorityClasses,
 	}); err != nil {
 		return err
 	}
@@ -64,6 +151,28 @@ func (m *maintenanceRunner) prepareWorkloads() error {
 	); err != nil {
 		return err
 	}
+	m.recordLatencies(m.ctx, k8s.TriggerInitial, time.Time{})
+	return nil
+}
+
+// ensurePriorityClasses creates the PriorityClasses of the mix's tiers.
+// They are cluster-scoped and carry the run ID, so cleanup removes them
+// with the run.
+func (m *maintenanceRunner) ensurePriorityClasses() error {
+	values := workloads.DefaultPriorityTiers()
+	for tier, name := range m.cfg.PriorityClasses {
+		value, ok := values[tier]
+		if !ok {
+			return fmt.Errorf("unknown priority tier %q", tier)
+		}
+		if err := k8s.EnsurePriorityClass(m.ctx, m.client, k8s.BuildPriorityClass(name, value, m.cfg.RunID)); err != nil {
+			return err
+		}
+		m.logger.Info("priority class ready",
+			logging.StringField("name", name),
+			logging.StringField("value", fmt.Sprintf("%d", value)),
+		)
+	}
 	return nil
 }
 
@@ -71,6 +180,16 @@ func (m *maintenanceRunner) snapshotBefore() error {
 	return m.mark("snapshot:before")
 }
 
+// validateWindow rejects an empty post-uncordon wait for a CronJob or
+// Deployment descheduler: the wait is the window their activity is measured
+// over, so nothing could be observed.
+func (m *maintenanceRunner) validateWindow() error {
+	if m.cfg.DeschedulerPolicy == "" || m.deschedulerMode() == descheduler.ModeJob || m.cfg.PostUncordonWait > 0 {
+		return nil
+	}
+	return fmt.Errorf("post-uncordon wait must be positive in %s mode, where it is the window descheduler activity is measured over", m.deschedulerMode())
+}
+
 func (m *maintenanceRunner) validateIterations(iterations int) error {
 	if iterations <= 1 {
 		return nil
@@ -104,51 +223,55 @@ func (m *maintenanceRunner) selectDrainNode() error {
 	m.drainedNodes[drainNode] = struct{}{}
 	m.logger.Info("selected drain node",
 		logging.StringField("node", drainNode),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 	)
 	return nil
 }
 
-func (m *maintenanceRunner) cordonAndDrain() error {
+func (m *maintenanceRunner) cordon() error {
+	m.span.SetAttributes(attribute.String("node", m.drainNode))
 	if err := m.mark("cordon:start",
 		logging.StringField("node", m.drainNode),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 	); err != nil {
 		return err
 	}
-	if err := k8s.CordonNode(m.ctx, m.client, m.drainNode); err != nil {
+	if err := m.recordCordon(true); err != nil {
 		return err
 	}
-	if err := m.mark("cordon:done",
-		logging.StringField("node", m.drainNode),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
-	); err != nil {
+	if err := k8s.CordonNode(m.ctx, m.client, m.drainNode, m.cfg.RunID); err != nil {
 		return err
 	}
+	return m.mark("cordon:done",
+		logging.StringField("node", m.drainNode),
+	)
+}
 
+func (m *maintenanceRunner) drain() error {
+	m.span.SetAttributes(attribute.String("node", m.drainNode))
+	m.drainStart = time.Now()
 	podsOnNode, err := countPodsOnNode(m.ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector, m.drainNode)
 	if err == nil {
+		m.span.SetAttributes(attribute.Int("pods", podsOnNode))
 		if err := m.mark("drain:start",
 			logging.StringField("node", m.drainNode),
 			logging.StringField("pods", fmt.Sprintf("%d", podsOnNode)),
-			logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 		); err != nil {
 			return err
 		}
 	} else {
 		if err := m.mark("drain:start",
 			logging.StringField("node", m.drainNode),
-			logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 		); err != nil {
 			return err
 		}
 	}
+	if pods, err := m.trackPriorities(m.ctx); err == nil {
+		m.disruptions = append(m.disruptions, k8s.DrainDisruptions(pods, m.drainNode, m.iteration, time.Now())...)
+	}
 	if err := k8s.DrainNode(m.ctx, m.client, m.drainNode, k8s.DrainOptions{Namespace: m.cfg.Namespace, LabelSelector: m.cfg.LabelSelector, Timeout: m.cfg.WaitTimeout}); err != nil {
 		return err
 	}
 	if err := m.mark("drain:done",
 		logging.StringField("node", m.drainNode),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 	); err != nil {
 		return err
 	}
@@ -157,6 +280,13 @@ func (m *maintenanceRunner) cordonAndDrain() error {
 
 func (m *maintenanceRunner) waitForReschedule() error {
 	expectedPods := m.totalPods
+	// Marked before waiting so a timeout is reported against this step
+	// rather than the drain before it.
+	if err := m.mark("reschedule:start",
+		logging.StringField("pods", fmt.Sprintf("%d", expectedPods)),
+	); err != nil {
+		return err
+	}
 	if err := k8s.WaitForPodsReady(m.ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector, expectedPods, m.cfg.WaitTimeout); err != nil {
 		if errors.Is(err, wait.ErrWaitTimeout) {
 			if summary, sumErr := k8s.SummarizeScheduling(m.ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector); sumErr == nil {
@@ -172,32 +302,42 @@ func (m *maintenanceRunner) waitForReschedule() error {
 		logSchedulingSummary(m.ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector, m.logger)
 		return err
 	}
+	m.span.SetAttributes(attribute.Int("pods", int(expectedPods)))
 	m.logger.Info("pods ready after drain", logging.StringField("pods", fmt.Sprintf("%d", expectedPods)))
+	m.recordLatencies(m.ctx, k8s.TriggerDrain, m.drainStart)
+	// Drains evict through the API, which records no event, so only
+	// preemptions come from events here.
+	m.recordDisruptions(k8s.TriggerDrain, m.drainStart, k8s.CausePreempted)
 	return m.mark("reschedule:ready",
 		logging.StringField("pods", fmt.Sprintf("%d", expectedPods)),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 	)
 }
 
 func (m *maintenanceRunner) uncordon() error {
+	m.span.SetAttributes(attribute.String("node", m.drainNode))
 	if err := m.mark("uncordon:start",
 		logging.StringField("node", m.drainNode),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 	); err != nil {
 		return err
 	}
-	if err := k8s.UncordonNode(m.ctx, m.client, m.drainNode); err != nil {
+	if err := k8s.RestoreNode(m.ctx, m.client, m.drainNode); err != nil {
+		return err
+	}
+	if err := m.recordCordon(false); err != nil {
 		return err
 	}
 	return m.mark("uncordon:done",
 		logging.StringField("node", m.drainNode),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 	)
 }
 
 func (m *maintenanceRunner) capturePreEvictions() error {
 	if pods, err := k8s.ListPods(m.ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector); err == nil {
 		m.preEvictLabels = k8s.PodNameToAppLabel(pods)
+		m.preEvictKinds = k8s.PodNameToKind(pods)
+		for name, tier := range k8s.PodNameToPriority(pods) {
+			m.priorities[name] = tier
+		}
 	}
 	return nil
 }
@@ -206,18 +346,17 @@ func (m *maintenanceRunner) ensureDescheduler() error {
 	if m.cfg.DeschedulerPolicy == "" {
 		return nil
 	}
-	if err := m.mark("descheduler:install"); err != nil {
+	if err := m.mark("descheduler:install", logging.StringField("mode", m.deschedulerMode())); err != nil {
 		return err
 	}
-	if err := descheduler.EnsureInstalled(m.ctx, m.client, descheduler.Config{
-		Namespace:    m.cfg.DeschedulerNS,
-		Image:        m.cfg.DeschedulerImage,
-		PolicyYAML:   m.cfg.DeschedulerPolicy,
-		CronSchedule: m.cfg.DeschedulerCron,
-	}); err != nil {
+	cfg := m.deschedulerConfig("")
+	if err := descheduler.EnsureInstalled(m.ctx, m.client, cfg); err != nil {
+		return err
+	}
+	if err := descheduler.Start(m.ctx, m.client, cfg); err != nil {
 		return err
 	}
-	m.logger.Info("descheduler installed")
+	m.logger.Info("descheduler installed", logging.StringField("mode", m.deschedulerMode()))
 	return nil
 }
 
@@ -225,47 +364,251 @@ func (m *maintenanceRunner) runDescheduler() error {
 	if m.cfg.DeschedulerPolicy == "" {
 		return nil
 	}
-	if err := m.mark("descheduler:run", logging.StringField("iteration", fmt.Sprintf("%d", m.iteration))); err != nil {
+	if err := m.mark("descheduler:run"); err != nil {
 		return err
 	}
+	m.deschedulerStart = time.Now()
+	// Replacements created by the drain are not known yet.
+	_, _ = m.trackPriorities(m.ctx)
+	if m.deschedulerMode() != descheduler.ModeJob {
+		// CronJob and Deployment modes run on their own schedule; their
+		// activity is measured over the post-uncordon window, which the
+		// step spans.
+		stopMetrics := m.collectMetrics()
+		if err := m.waitPostUncordon(); err != nil {
+			stopMetrics()
+			return err
+		}
+		scraped, warning := stopMetrics()
+		return m.recordDeschedulerActivity(scraped, warning, "app=deschedbench-descheduler", m.deschedulerStart)
+	}
 	jobName := fmt.Sprintf("deschedbench-descheduler-%s-%d", m.cfg.RunID, m.iteration)
-	if err := descheduler.RunOnce(m.ctx, m.client, descheduler.Config{
+	m.span.SetAttributes(attribute.String("job", jobName))
+	if err := descheduler.RunOnce(m.ctx, m.client, m.deschedulerConfig(jobName)); err != nil {
+		return err
+	}
+	m.logger.Info("descheduler job created",
+		logging.StringField("job", jobName),
+	)
+
+	stopMetrics := m.collectMetrics()
+	err := descheduler.WaitForJob(m.ctx, m.client, m.cfg.DeschedulerNS, jobName, m.cfg.WaitTimeout)
+	scraped, warning := stopMetrics()
+	if err != nil {
+		return err
+	}
+	return m.recordDeschedulerActivity(scraped, warning, "job-name="+jobName, time.Time{})
+}
+
+// collectMetrics scrapes every descheduler pod in the background until the
+// returned function is called. That function returns the metrics the
+// iteration added and, when they are missing or may be partial, why.
+func (m *maintenanceRunner) collectMetrics() func() (*descheduler.Metrics, string) {
+	collector := descheduler.StartMetricsCollector(m.ctx, m.client, m.cfg.DeschedulerNS, 2*time.Second)
+	return func() (*descheduler.Metrics, string) {
+		scrapes, err := collector.Stop()
+		if err != nil {
+			m.logger.Warn("descheduler metrics unavailable", logging.ErrorField(err))
+			return nil, "metrics unavailable: " + err.Error()
+		}
+		deltas := make([]descheduler.Metrics, 0, len(scrapes))
+		var partial []string
+		for _, scrape := range scrapes {
+			// Counters run for the life of a pod, so a pod already seen
+			// in an earlier iteration adds only what it counted since.
+			delta := scrape
+			if prev, ok := m.lastMetrics[scrape.Source]; ok {
+				delta = scrape.Since(prev)
+			}
+			m.lastMetrics[scrape.Source] = scrape
+			deltas = append(deltas, delta)
+			if m.deschedulerMode() != descheduler.ModeDeployment && !scrape.Looped() {
+				partial = append(partial, scrape.Source)
+			}
+		}
+		merged := descheduler.MergeMetrics(deltas)
+		if len(partial) == 0 {
+			return &merged, ""
+		}
+		warning := fmt.Sprintf("last scrape of %s was taken before its loop finished; counters may be partial", strings.Join(partial, ","))
+		m.logger.Warn("descheduler metrics partial", logging.StringField("pods", strings.Join(partial, ",")))
+		return &merged, warning
+	}
+}
+
+func (m *maintenanceRunner) recordDeschedulerActivity(scraped *descheduler.Metrics, metricsWarning, logSelector string, logSince time.Time) error {
+	if m.imageID == "" {
+		if imageID, err := descheduler.ResolveImageID(m.ctx, m.client, m.cfg.DeschedulerNS); err == nil {
+			m.imageID = imageID
+			m.logger.Info("descheduler image resolved",
+				logging.StringField("image", m.cfg.DeschedulerImage),
+				logging.StringField("image_id", imageID),
+			)
+		}
+	}
+	var decisions *descheduler.Decisions
+	if parsed, err := descheduler.FetchDecisions(m.ctx, m.client, m.cfg.DeschedulerNS, logSelector, logSince); err == nil {
+		decisions = &parsed
+		m.logger.Info("descheduler decisions parsed",
+			logging.StringField("nodes_classified", fmt.Sprintf("%d", len(parsed.NodeClassifications))),
+			logging.StringField("evictions", fmt.Sprintf("%d", len(parsed.Evictions))),
+			logging.StringField("limit_hits", fmt.Sprintf("%d", len(parsed.LimitHits))),
+		)
+	} else {
+		m.logger.Warn("descheduler logs unavailable", logging.ErrorField(err))
+	}
+
+	activity, err := descheduler.MeasureActivity(m.ctx, m.client, m.deschedulerConfig(""), m.cfg.Namespace, m.iteration, m.deschedulerStart, time.Now())
+	if err != nil {
+		m.logger.Warn("descheduler activity unavailable", logging.ErrorField(err))
+	} else {
+		activity.Metrics = scraped
+		activity.MetricsWarning = metricsWarning
+		activity.Decisions = decisions
+		m.activity = append(m.activity, activity)
+		m.span.SetAttributes(attribute.Int("evictions", activity.Evictions))
+	}
+	m.recordDisruptions(k8s.TriggerDescheduler, m.deschedulerStart, k8s.CauseEvicted, k8s.CausePreempted)
+	if scraped != nil {
+		m.logger.Info("descheduler metrics scraped",
+			logging.StringField("source", scraped.Source),
+			logging.StringField("evicted", descheduler.FormatEvictionsByStrategy(scraped.EvictionsByStrategy())),
+		)
+	}
+	return m.mark("descheduler:done",
+		logging.StringField("evictions", fmt.Sprintf("%d", activity.Evictions)),
+	)
+}
+
+func (m *maintenanceRunner) deschedulerMode() string {
+	if m.cfg.DeschedulerMode == "" {
+		return descheduler.ModeJob
+	}
+	return m.cfg.DeschedulerMode
+}
+
+func (m *maintenanceRunner) deschedulerConfig(jobName string) descheduler.Config {
+	return descheduler.Config{
 		Namespace:    m.cfg.DeschedulerNS,
 		Image:        m.cfg.DeschedulerImage,
 		PolicyYAML:   m.cfg.DeschedulerPolicy,
+		Mode:         m.deschedulerMode(),
 		CronSchedule: m.cfg.DeschedulerCron,
+		Interval:     m.cfg.DeschedulerInterval,
 		JobName:      jobName,
-	}); err != nil {
-		return err
+		RunID:        m.cfg.RunID,
 	}
-	m.logger.Info("descheduler job created",
-		logging.StringField("job", jobName),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
-	)
-	return m.mark("descheduler:done", logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)))
 }
 
+// waitPostUncordon waits PostUncordonWait, or until the run is cancelled.
 func (m *maintenanceRunner) waitPostUncordon() error {
 	if m.cfg.PostUncordonWait <= 0 {
 		return nil
 	}
 	m.logger.Info("waiting after uncordon",
 		logging.StringField("duration", m.cfg.PostUncordonWait.String()),
-		logging.StringField("iteration", fmt.Sprintf("%d", m.iteration)),
 	)
-	time.Sleep(m.cfg.PostUncordonWait)
-	return nil
+	timer := time.NewTimer(m.cfg.PostUncordonWait)
+	defer timer.Stop()
+	select {
+	case <-m.ctx.Done():
+		return m.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func (m *maintenanceRunner) snapshotAfter() error {
 	return m.mark("snapshot:after")
 }
 
-func (m *maintenanceRunner) collectEvictions() []k8s.EvictionRecord {
+// recordLatencies captures the startup latencies of benchmark pods created
+// at or after since. Pods are captured right after they were needed, since a
+// later drain or descheduler run may evict them. A pod seen before keeps its
+// trigger and iteration.
+func (m *maintenanceRunner) recordLatencies(ctx context.Context, trigger string, since time.Time) {
+	pods, err := k8s.ListPods(ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector)
+	if err != nil {
+		m.logger.Warn("pod latencies unavailable", logging.StringField("trigger", trigger), logging.ErrorField(err))
+		return
+	}
+	for _, rec := range k8s.PodLatencies(pods, trigger, m.iteration, since) {
+		if _, ok := m.latencies[rec.PodName]; !ok {
+			m.latencies[rec.PodName] = rec
+		}
+	}
+}
+
+// refreshLatencies updates the timestamps of pods already captured, so a
+// pod still starting at its capture is complete in the result.
+func (m *maintenanceRunner) refreshLatencies(ctx context.Context) {
+	if len(m.latencies) == 0 {
+		return
+	}
+	pods, err := k8s.ListPods(ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector)
+	if err != nil {
+		return
+	}
+	for _, rec := range k8s.PodLatencies(pods, "", 0, time.Time{}) {
+		if prev, ok := m.latencies[rec.PodName]; ok {
+			rec.Trigger = prev.Trigger
+			rec.Iteration = prev.Iteration
+			m.latencies[rec.PodName] = rec
+		}
+	}
+}
+
+func (m *maintenanceRunner) podLatencies() []k8s.PodLatency {
+	out := make([]k8s.PodLatency, 0, len(m.latencies))
+	for _, rec := range m.latencies {
+		out = append(out, rec)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].PodName < out[j].PodName
+	})
+	return out
+}
+
+// trackPriorities lists the benchmark pods and remembers their priority
+// tiers, so disruption events of pods already gone can still be tagged.
+func (m *maintenanceRunner) trackPriorities(ctx context.Context) ([]corev1.Pod, error) {
+	pods, err := k8s.ListPods(ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector)
+	if err != nil {
+		m.logger.Warn("pod priorities unavailable", logging.ErrorField(err))
+		return nil, err
+	}
+	for name, tier := range k8s.PodNameToPriority(pods) {
+		m.priorities[name] = tier
+	}
+	return pods, nil
+}
+
+// recordDisruptions adds the disruption events since start with one of
+// causes, attributed to trigger and the current iteration.
+func (m *maintenanceRunner) recordDisruptions(trigger string, start time.Time, causes ...string) {
+	disruptions, err := k8s.CollectDisruptions(m.ctx, m.client, m.cfg.Namespace, m.priorities, start, time.Now())
+	if err != nil {
+		m.logger.Warn("disruption events unavailable", logging.StringField("trigger", trigger), logging.ErrorField(err))
+		return
+	}
+	for _, d := range disruptions {
+		if !slices.Contains(causes, d.Cause) {
+			continue
+		}
+		d.Trigger = trigger
+		d.Iteration = m.iteration
+		m.disruptions = append(m.disruptions, d)
+	}
+}
+
+func (m *maintenanceRunner) collectEvictions(ctx context.Context) []k8s.EvictionRecord {
 	var evictions []k8s.EvictionRecord
-	postPods, err := k8s.ListPods(m.ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector)
+	postPods, err := k8s.ListPods(ctx, m.client, m.cfg.Namespace, m.cfg.LabelSelector)
 	if err == nil {
-		evictions, _ = k8s.CollectEvictions(m.ctx, m.client, m.cfg.Namespace, m.preEvictLabels, postPods)
+		evictions, _ = k8s.CollectEvictions(ctx, m.client, m.cfg.Namespace, m.preEvictLabels, m.preEvictKinds, postPods)
 	}
 	return evictions
 }
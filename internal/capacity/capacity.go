@@ -0,0 +1,324 @@
+package capacity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/workloads"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Node is the capacity left on a worker after existing pod requests.
+type Node struct {
+	Name     string `json:"name"`
+	CPUMilli int64  `json:"cpu_milli"`
+	MemBytes int64  `json:"mem_bytes"`
+	Pods     int64  `json:"pods"`
+}
+
+type PodShape struct {
+	Class    string
+	CPUMilli int64
+	MemBytes int64
+	Count    int32
+}
+
+type DrainCheck struct {
+	Iteration  int      `json:"iteration"`
+	Drained    []string `json:"drained"`
+	Workers    int      `json:"workers"`
+	Feasible   bool     `json:"feasible"`
+	Unplaced   int32    `json:"unplaced"`
+	NeedCPU    int64    `json:"need_cpu_milli"`
+	FreeCPU    int64    `json:"free_cpu_milli"`
+	NeedMemory int64    `json:"need_mem_bytes"`
+	FreeMemory int64    `json:"free_mem_bytes"`
+	NeedPods   int64    `json:"need_pods"`
+	FreePods   int64    `json:"free_pods"`
+}
+
+type Report struct {
+	Feasible     bool         `json:"feasible"`
+	PodsTotal    int32        `json:"pods_total"`
+	MaxPodsTotal int32        `json:"max_pods_total"`
+	Checks       []DrainCheck `json:"checks"`
+}
+
+// FreeNodes computes the free capacity of schedulable workers. Requests of
+// pods in excludeNamespace are ignored so a benchmark namespace left over
+// from a retry does not count against itself.
+func FreeNodes(nodes []corev1.Node, pods []corev1.Pod, excludeNamespace string) []Node {
+	byName := map[string]int{}
+	out := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || k8s.IsControlPlaneNode(node.Labels) {
+			continue
+		}
+		byName[node.Name] = len(out)
+		out = append(out, Node{
+			Name:     node.Name,
+			CPUMilli: node.Status.Allocatable.Cpu().MilliValue(),
+			MemBytes: node.Status.Allocatable.Memory().Value(),
+			Pods:     node.Status.Allocatable.Pods().Value(),
+		})
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if excludeNamespace != "" && pod.Namespace == excludeNamespace {
+			continue
+		}
+		idx, ok := byName[pod.Spec.NodeName]
+		if !ok {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			out[idx].CPUMilli -= container.Resources.Requests.Cpu().MilliValue()
+			out[idx].MemBytes -= container.Resources.Requests.Memory().Value()
+		}
+		out[idx].Pods--
+	}
+	return out
+}
+
+// Shapes converts a workload mix into pod shapes, largest first. Each mix
+// key becomes its own shape, sized by the key's size class.
+func Shapes(mix workloads.Mix, sizes map[string]workloads.SizeClass) ([]PodShape, error) {
+	shapes := make([]PodShape, 0, len(mix))
+	for key, count := range mix {
+		if count == 0 {
+			continue
+		}
+		_, class := workloads.SplitMixKey(key)
+		size, ok := sizes[class]
+		if !ok {
+			return nil, fmt.Errorf("size class %q not defined", class)
+		}
+		cpu, err := resource.ParseQuantity(size.CPU)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu for size class %q: %w", class, err)
+		}
+		mem, err := resource.ParseQuantity(size.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory for size class %q: %w", class, err)
+		}
+		shapes = append(shapes, PodShape{Class: key, CPUMilli: cpu.MilliValue(), MemBytes: mem.Value(), Count: count})
+	}
+	sortShapes(shapes)
+	return shapes, nil
+}
+
+// ReserveNoise places the pods of every noise workload, which a run creates
+// before the benchmark workloads, and returns the capacity left and how many
+// noise pods did not fit.
+func ReserveNoise(nodes []Node, noise []workloads.WorkloadConfig) ([]Node, int32, error) {
+	var unplaced int32
+	for _, cfg := range noise {
+		shapes, err := Shapes(cfg.Mix, cfg.SizeClasses)
+		if err != nil {
+			return nil, 0, err
+		}
+		var n int32
+		nodes, n = Reserve(nodes, shapes)
+		unplaced += n
+	}
+	return nodes, unplaced, nil
+}
+
+// DrainOrder predicts which workers each iteration drains, mirroring the
+// benchmark's selection: the first `parallel` workers not drained before.
+func DrainOrder(nodes []Node, iterations, parallel int) [][]string {
+	if parallel <= 0 {
+		parallel = 1
+	}
+	order := make([][]string, 0, iterations)
+	next := 0
+	for i := 0; i < iterations; i++ {
+		var drained []string
+		for len(drained) < parallel && next < len(nodes) {
+			drained = append(drained, nodes[next].Name)
+			next++
+		}
+		order = append(order, drained)
+	}
+	return order
+}
+
+// PlanDrains checks that every shape fits on the workers left after each
+// planned drain, and finds the largest proportional pod count that fits all.
+func PlanDrains(nodes []Node, order [][]string, shapes []PodShape) Report {
+	report := Report{Feasible: true, PodsTotal: totalPods(shapes)}
+	for i, drained := range order {
+		check := checkDrain(nodes, drained, shapes)
+		check.Iteration = i + 1
+		if !check.Feasible {
+			report.Feasible = false
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	report.MaxPodsTotal = maxFitting(nodes, order, shapes)
+	return report
+}
+
+func checkDrain(nodes []Node, drained []string, shapes []PodShape) DrainCheck {
+	remaining := withoutNodes(nodes, drained)
+	check := DrainCheck{Drained: drained, Workers: len(remaining)}
+	for _, node := range remaining {
+		check.FreeCPU += node.CPUMilli
+		check.FreeMemory += node.MemBytes
+		check.FreePods += node.Pods
+	}
+	for _, shape := range shapes {
+		check.NeedCPU += shape.CPUMilli * int64(shape.Count)
+		check.NeedMemory += shape.MemBytes * int64(shape.Count)
+		check.NeedPods += int64(shape.Count)
+	}
+	check.Unplaced = place(remaining, shapes)
+	check.Feasible = check.Unplaced == 0
+	return check
+}
+
+// place packs pods onto the node with the most free CPU, like the scheduler's
+// LeastAllocated scoring, and returns how many pods could not be placed.
+func place(nodes []Node, shapes []PodShape) int32 {
+	_, unplaced := Reserve(nodes, shapes)
+	return unplaced
+}
+
+// Reserve places shapes the way place does and returns the capacity left
+// afterwards and how many pods did not fit. Dry runs use it for workloads
+// that do not exist yet, such as noise.
+func Reserve(nodes []Node, shapes []PodShape) ([]Node, int32) {
+	free := make([]Node, len(nodes))
+	copy(free, nodes)
+	var unplaced int32
+	for _, shape := range shapes {
+		for n := int32(0); n < shape.Count; n++ {
+			best := -1
+			for i := range free {
+				if free[i].Pods < 1 || free[i].CPUMilli < shape.CPUMilli || free[i].MemBytes < shape.MemBytes {
+					continue
+				}
+				if best < 0 || free[i].CPUMilli > free[best].CPUMilli {
+					best = i
+				}
+			}
+			if best < 0 {
+				unplaced++
+				continue
+			}
+			free[best].CPUMilli -= shape.CPUMilli
+			free[best].MemBytes -= shape.MemBytes
+			free[best].Pods--
+		}
+	}
+	return free, unplaced
+}
+
+func maxFitting(nodes []Node, order [][]string, shapes []PodShape) int32 {
+	total := totalPods(shapes)
+	fits := func(target int32) bool {
+		scaled := scaleShapes(shapes, total, target)
+		for _, drained := range order {
+			if place(withoutNodes(nodes, drained), scaled) > 0 {
+				return false
+			}
+		}
+		return true
+	}
+	if total == 0 || fits(total) {
+		return total
+	}
+	lo, hi := int32(0), total
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
+}
+
+func scaleShapes(shapes []PodShape, total, target int32) []PodShape {
+	out := make([]PodShape, len(shapes))
+	var assigned int32
+	for i, shape := range shapes {
+		out[i] = shape
+		out[i].Count = int32(int64(shape.Count) * int64(target) / int64(total))
+		assigned += out[i].Count
+	}
+	// Hand out rounding leftovers to the smallest shapes first.
+	for i := len(out) - 1; assigned < target && i >= 0; i-- {
+		if out[i].Count < shapes[i].Count {
+			out[i].Count++
+			assigned++
+		}
+	}
+	return out
+}
+
+func withoutNodes(nodes []Node, drained []string) []Node {
+	skip := map[string]struct{}{}
+	for _, name := range drained {
+		skip[name] = struct{}{}
+	}
+	out := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		if _, ok := skip[node.Name]; ok {
+			continue
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+func totalPods(shapes []PodShape) int32 {
+	var total int32
+	for _, shape := range shapes {
+		total += shape.Count
+	}
+	return total
+}
+
+func sortShapes(shapes []PodShape) {
+	sort.Slice(shapes, func(i, j int) bool {
+		if shapes[i].CPUMilli != shapes[j].CPUMilli {
+			return shapes[i].CPUMilli > shapes[j].CPUMilli
+		}
+		if shapes[i].MemBytes != shapes[j].MemBytes {
+			return shapes[i].MemBytes > shapes[j].MemBytes
+		}
+		return shapes[i].Class < shapes[j].Class
+	})
+}
+
+// Format renders the report as one line per planned drain.
+func Format(report Report) string {
+	lines := make([]string, 0, len(report.Checks)+1)
+	for _, check := range report.Checks {
+		status := "ok"
+		if !check.Feasible {
+			status = fmt.Sprintf("INFEASIBLE (%d pods unplaced)", check.Unplaced)
+		}
+		lines = append(lines, fmt.Sprintf("iteration %d drain %s -> %d workers: cpu %dm/%dm, memory %dMi/%dMi, pods %d/%d: %s",
+			check.Iteration,
+			strings.Join(check.Drained, ","),
+			check.Workers,
+			check.NeedCPU, check.FreeCPU,
+			check.NeedMemory>>20, check.FreeMemory>>20,
+			check.NeedPods, check.FreePods,
+			status,
+		))
+	}
+	if !report.Feasible {
+		lines = append(lines, fmt.Sprintf("largest pod count that fits every drain: %d (requested %d)", report.MaxPodsTotal, report.PodsTotal))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,71 @@
+package capacity
+
+import (
+	"testing"
+)
+
+func TestPlanDrains(t *testing.T) {
+	nodes := []Node{
+		{Name: "w1", CPUMilli: 1000, MemBytes: 4 << 30, Pods: 110},
+		{Name: "w2", CPUMilli: 1000, MemBytes: 4 << 30, Pods: 110},
+		{Name: "w3", CPUMilli: 1000, MemBytes: 4 << 30, Pods: 110},
+	}
+	order := DrainOrder(nodes, 2, 1)
+	if len(order) != 2 || order[0][0] != "w1" || order[1][0] != "w2" {
+		t.Fatalf("unexpected drain order: %v", order)
+	}
+
+	shapes := []PodShape{{Class: "small", CPUMilli: 100, MemBytes: 128 << 20, Count: 20}}
+	report := PlanDrains(nodes, order, shapes)
+	if !report.Feasible || report.MaxPodsTotal != 20 {
+		t.Fatalf("expected feasible plan, got %+v", report)
+	}
+
+	shapes[0].Count = 25
+	report = PlanDrains(nodes, order, shapes)
+	if report.Feasible {
+		t.Fatalf("expected infeasible plan, got %+v", report)
+	}
+	if report.Checks[0].Unplaced != 5 {
+		t.Fatalf("expected 5 unplaced pods, got %d", report.Checks[0].Unplaced)
+	}
+	if report.MaxPodsTotal != 20 {
+		t.Fatalf("expected max 20 pods, got %d", report.MaxPodsTotal)
+	}
+}
+
+func TestPlanDrainsFragmentation(t *testing.T) {
+	// 1200m is free in total after the drain, but no single worker fits a 700m pod.
+	nodes := []Node{
+		{Name: "w1", CPUMilli: 2000, MemBytes: 8 << 30, Pods: 110},
+		{Name: "w2", CPUMilli: 600, MemBytes: 8 << 30, Pods: 110},
+		{Name: "w3", CPUMilli: 600, MemBytes: 8 << 30, Pods: 110},
+	}
+	shapes := []PodShape{{Class: "large", CPUMilli: 500, MemBytes: 1 << 30, Count: 2}, {Class: "small", CPUMilli: 100, MemBytes: 1 << 20, Count: 2}}
+	report := PlanDrains(nodes, DrainOrder(nodes, 1, 1), shapes)
+	if !report.Feasible {
+		t.Fatalf("expected feasible plan, got %+v", report)
+	}
+	shapes[0].CPUMilli = 700
+	report = PlanDrains(nodes, DrainOrder(nodes, 1, 1), shapes)
+	if report.Feasible {
+		t.Fatalf("expected fragmentation to make plan infeasible, got %+v", report)
+	}
+}
+
+func TestReserve(t *testing.T) {
+	nodes := []Node{
+		{Name: "w1", CPUMilli: 1000, MemBytes: 4 << 30, Pods: 110},
+		{Name: "w2", CPUMilli: 400, MemBytes: 4 << 30, Pods: 110},
+	}
+	free, unplaced := Reserve(nodes, []PodShape{{Class: "large", CPUMilli: 500, MemBytes: 512 << 20, Count: 3}})
+	if unplaced != 1 {
+		t.Fatalf("expected 1 unplaced pod, got %d", unplaced)
+	}
+	if free[0].CPUMilli != 0 || free[0].Pods != 108 || free[1].CPUMilli != 400 {
+		t.Fatalf("unexpected capacity left: %+v", free)
+	}
+	if nodes[0].CPUMilli != 1000 {
+		t.Fatalf("expected input nodes untouched, got %+v", nodes[0])
+	}
+}
@@ -0,0 +1,148 @@
+package descheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Activity struct {
+	Iteration          int       `json:"iteration"`
+	Mode               string    `json:"mode"`
+	Start              time.Time `json:"start"`
+	End                time.Time `json:"end"`
+	Runs               int       `json:"runs,omitempty"`
+	Evictions          int       `json:"evictions"`
+	EvictionsPerMinute float64   `json:"evictions_per_minute"`
+	Metrics            *Metrics  `json:"metrics,omitempty"`
+	// MetricsWarning says why Metrics is missing or may be partial.
+	MetricsWarning string     `json:"metrics_warning,omitempty"`
+	Decisions      *Decisions `json:"decisions,omitempty"`
+}
+
+// WaitForJob blocks until the named Job completes or fails.
+func WaitForJob(ctx context.Context, client kubernetes.Interface, namespace, name string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = 10 * time.Minute
+	}
+	var failed bool
+	err := wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		job, err := client.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, cond := range job.Status.Conditions {
+			if cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch cond.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				failed = true
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+	if failed {
+		return fmt.Errorf("descheduler job %s failed", name)
+	}
+	return nil
+}
+
+// MeasureActivity summarizes descheduler activity in the window [start, end]:
+// eviction events in podNamespace and, for CronJob mode, the number of Jobs the
+// CronJob spawned in that window. Job mode always counts as a single run.
+func MeasureActivity(ctx context.Context, client kubernetes.Interface, cfg Config, podNamespace string, iteration int, start, end time.Time) (Activity, error) {
+	mode := cfg.Mode
+	if mode == "" {
+		mode = ModeJob
+	}
+	activity := Activity{
+		Iteration: iteration,
+		Mode:      mode,
+		Start:     start,
+		End:       end,
+	}
+	evictions, err := k8s.CountEvictionEvents(ctx, client, podNamespace, start, end)
+	if err != nil {
+		return activity, err
+	}
+	activity.Evictions = evictions
+	if minutes := end.Sub(start).Minutes(); minutes > 0 {
+		activity.EvictionsPerMinute = float64(evictions) / minutes
+	}
+
+	switch mode {
+	case ModeJob:
+		activity.Runs = 1
+	case ModeCronJob:
+		runs, err := countCronJobRuns(ctx, client, cfg.Namespace, start, end)
+		if err != nil {
+			return activity, err
+		}
+		activity.Runs = runs
+	}
+	return activity, nil
+}
+
+func countCronJobRuns(ctx context.Context, client kubernetes.Interface, namespace string, start, end time.Time) (int, error) {
+	jobs, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=deschedbench-descheduler",
+	})
+	if err != nil {
+		return 0, err
+	}
+	runs := 0
+	for _, job := range jobs.Items {
+		if !ownedByCronJob(job.OwnerReferences) {
+			continue
+		}
+		created := job.CreationTimestamp.Time
+		if created.Before(start) || created.After(end) {
+			continue
+		}
+		runs++
+	}
+	return runs, nil
+}
+
+func ownedByCronJob(refs []metav1.OwnerReference) bool {
+	for _, ref := range refs {
+		if ref.Kind == "CronJob" {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveImageID returns the image ID (registry digest) reported in the status
+// of a descheduler pod, which pins the exact build behind a tag.
+func ResolveImageID(ctx context.Context, client kubernetes.Interface, namespace string) (string, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=deschedbench-descheduler",
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == "descheduler" && status.ImageID != "" {
+				return status.ImageID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no descheduler pod reports an image ID in %s", namespace)
+}
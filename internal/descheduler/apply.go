@@ -6,6 +6,7 @@ import (
 	"io"
 	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -54,6 +55,10 @@ func applyObject(ctx context.Context, client kubernetes.Interface, obj runtime.O
 		return upsertService(ctx, client, typed)
 	case *batchv1.Job:
 		return upsertJob(ctx, client, typed)
+	case *batchv1.CronJob:
+		return upsertCronJob(ctx, client, typed)
+	case *appsv1.Deployment:
+		return upsertDeployment(ctx, client, typed)
 	default:
 		return fmt.Errorf("unsupported object type %T", obj)
 	}
@@ -144,3 +149,31 @@ func upsertJob(ctx context.Context, client kubernetes.Interface, job *batchv1.Jo
 	_, err = client.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{})
 	return err
 }
+
+func upsertCronJob(ctx context.Context, client kubernetes.Interface, cronJob *batchv1.CronJob) error {
+	current, err := client.BatchV1().CronJobs(cronJob.Namespace).Get(ctx, cronJob.Name, metav1.GetOptions{})
+	if err == nil && current != nil {
+		cronJob.ResourceVersion = current.ResourceVersion
+		_, err = client.BatchV1().CronJobs(cronJob.Namespace).Update(ctx, cronJob, metav1.UpdateOptions{})
+		return err
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	_, err = client.BatchV1().CronJobs(cronJob.Namespace).Create(ctx, cronJob, metav1.CreateOptions{})
+	return err
+}
+
+func upsertDeployment(ctx context.Context, client kubernetes.Interface, dep *appsv1.Deployment) error {
+	current, err := client.AppsV1().Deployments(dep.Namespace).Get(ctx, dep.Name, metav1.GetOptions{})
+	if err == nil && current != nil {
+		dep.ResourceVersion = current.ResourceVersion
+		_, err = client.AppsV1().Deployments(dep.Namespace).Update(ctx, dep, metav1.UpdateOptions{})
+		return err
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	_, err = client.AppsV1().Deployments(dep.Namespace).Create(ctx, dep, metav1.CreateOptions{})
+	return err
+}
@@ -2,16 +2,30 @@ package descheduler
 
 import (
 	"context"
+	"time"
 
 	"k8s.io/client-go/kubernetes"
 )
 
+// RBACName names the ClusterRole and ClusterRoleBinding created for the
+// descheduler of one run. They are cluster-scoped, so the run ID keeps
+// concurrent runs from taking over each other's objects.
+func RBACName(runID string) string {
+	if runID == "" {
+		return "deschedbench-descheduler"
+	}
+	return "deschedbench-descheduler-" + runID
+}
+
 type Config struct {
 	Namespace    string
 	Image        string
 	PolicyYAML   string
+	Mode         string
 	CronSchedule string
+	Interval     time.Duration
 	JobName      string
+	RunID        string
 }
 
 func EnsureInstalled(ctx context.Context, client kubernetes.Interface, cfg Config) error {
@@ -34,3 +48,16 @@ func RunOnce(ctx context.Context, client kubernetes.Interface, cfg Config) error
 	}
 	return applyManifest(ctx, client, manifest)
 }
+
+// Start deploys the long-running descheduler workload for CronJob and
+// Deployment modes. Job mode has nothing to start; use RunOnce per iteration.
+func Start(ctx context.Context, client kubernetes.Interface, cfg Config) error {
+	if cfg.Mode == ModeJob || cfg.Mode == "" {
+		return nil
+	}
+	manifest, err := renderModeManifest(cfg)
+	if err != nil {
+		return err
+	}
+	return applyManifest(ctx, client, manifest)
+}
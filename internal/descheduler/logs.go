@@ -0,0 +1,299 @@
+package descheduler
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	NodeUnderutilized         = "underutilized"
+	NodeOverutilized          = "overutilized"
+	NodeAppropriatelyUtilized = "appropriately-utilized"
+)
+
+type Decisions struct {
+	NodeClassifications []NodeClassification `json:"node_classifications"`
+	Evictions           []LoggedEviction     `json:"evictions"`
+	Skips               []LoggedSkip         `json:"skips"`
+	LimitHits           []LimitHit           `json:"limit_hits"`
+	TotalEvicted        int                  `json:"total_evicted"`
+	LinesParsed         int                  `json:"lines_parsed"`
+}
+
+type NodeClassification struct {
+	Time            time.Time `json:"time"`
+	Node            string    `json:"node"`
+	Class           string    `json:"class"`
+	Usage           string    `json:"usage,omitempty"`
+	UsagePercentage string    `json:"usage_percentage,omitempty"`
+}
+
+type LoggedEviction struct {
+	Time     time.Time `json:"time"`
+	Pod      string    `json:"pod"`
+	Node     string    `json:"node"`
+	Strategy string    `json:"strategy"`
+	Profile  string    `json:"profile,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+type LoggedSkip struct {
+	Time    time.Time `json:"time"`
+	Pod     string    `json:"pod,omitempty"`
+	Node    string    `json:"node,omitempty"`
+	Message string    `json:"message"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+type LimitHit struct {
+	Time      time.Time `json:"time"`
+	Message   string    `json:"message"`
+	Limit     string    `json:"limit,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+}
+
+// FetchDecisions reads the logs of descheduler pods matching selector and
+// parses them into structured decisions. A non-zero since limits the logs to
+// lines written after that time.
+func FetchDecisions(ctx context.Context, client kubernetes.Interface, namespace, selector string, since time.Time) (Decisions, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return Decisions{}, err
+	}
+	opts := &corev1.PodLogOptions{Container: "descheduler"}
+	if !since.IsZero() {
+		sinceTime := metav1.NewTime(since)
+		opts.SinceTime = &sinceTime
+	}
+	var out Decisions
+	for _, pod := range pods.Items {
+		stream, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, opts).Stream(ctx)
+		if err != nil {
+			return out, err
+		}
+		parsed := ParseLogs(stream, time.Now().Year())
+		stream.Close()
+		out.merge(parsed)
+	}
+	return out, nil
+}
+
+// ParseLogs parses klog output written by the descheduler at --v=3. klog
+// headers carry no year, so the caller supplies one.
+func ParseLogs(r io.Reader, year int) Decisions {
+	var out Decisions
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line, ok := parseKlogLine(scanner.Text(), year)
+		if !ok {
+			continue
+		}
+		out.LinesParsed++
+		out.apply(line)
+	}
+	return out
+}
+
+func (d *Decisions) apply(line klogLine) {
+	msg := line.Message
+	lower := strings.ToLower(msg)
+	switch {
+	case msg == "Node is underutilized":
+		d.addNode(line, NodeUnderutilized)
+	case msg == "Node is overutilized":
+		d.addNode(line, NodeOverutilized)
+	case msg == "Node is appropriately utilized":
+		d.addNode(line, NodeAppropriatelyUtilized)
+	case msg == "Evicted pod":
+		d.Evictions = append(d.Evictions, LoggedEviction{
+			Time:     line.Time,
+			Pod:      line.Fields["pod"],
+			Node:     line.Fields["node"],
+			Strategy: line.Fields["strategy"],
+			Profile:  line.Fields["profile"],
+			Reason:   line.Fields["reason"],
+		})
+	case msg == "Number of evicted pods" || msg == "Total number of pods evicted":
+		if total, err := strconv.Atoi(firstField(line.Fields, "totalEvicted", "evictedPods")); err == nil {
+			d.TotalEvicted += total
+		}
+	case isLimitMessage(lower, line.Fields["err"]):
+		message := msg
+		if errMsg := line.Fields["err"]; errMsg != "" {
+			message = errMsg
+		}
+		d.LimitHits = append(d.LimitHits, LimitHit{
+			Time:      line.Time,
+			Message:   message,
+			Limit:     line.Fields["limit"],
+			Node:      line.Fields["node"],
+			Namespace: line.Fields["namespace"],
+		})
+	case isSkipMessage(lower):
+		d.Skips = append(d.Skips, LoggedSkip{
+			Time:    line.Time,
+			Pod:     line.Fields["pod"],
+			Node:    line.Fields["node"],
+			Message: msg,
+			Reason:  firstField(line.Fields, "err", "checks", "reason"),
+		})
+	}
+}
+
+func (d *Decisions) addNode(line klogLine, class string) {
+	d.NodeClassifications = append(d.NodeClassifications, NodeClassification{
+		Time:            line.Time,
+		Node:            line.Fields["node"],
+		Class:           class,
+		Usage:           line.Fields["usage"],
+		UsagePercentage: line.Fields["usagePercentage"],
+	})
+}
+
+func (d *Decisions) merge(other Decisions) {
+	d.NodeClassifications = append(d.NodeClassifications, other.NodeClassifications...)
+	d.Evictions = append(d.Evictions, other.Evictions...)
+	d.Skips = append(d.Skips, other.Skips...)
+	d.LimitHits = append(d.LimitHits, other.LimitHits...)
+	d.TotalEvicted += other.TotalEvicted
+	d.LinesParsed += other.LinesParsed
+}
+
+func isLimitMessage(lower, errMsg string) bool {
+	errLower := strings.ToLower(errMsg)
+	for _, text := range []string{lower, errLower} {
+		if strings.Contains(text, "maximum number of evicted pods") ||
+			strings.Contains(text, "max number of evictions") ||
+			strings.Contains(text, "eviction limit") {
+			return true
+		}
+	}
+	return false
+}
+
+func isSkipMessage(lower string) bool {
+	return strings.Contains(lower, "nothing to do") ||
+		strings.Contains(lower, "fails the following checks") ||
+		strings.Contains(lower, "error evicting pod") ||
+		strings.Contains(lower, "no evictable pods") ||
+		strings.Contains(lower, "skipping")
+}
+
+func firstField(fields map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if v := fields[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+type klogLine struct {
+	Severity string
+	Time     time.Time
+	Message  string
+	Fields   map[string]string
+}
+
+var klogHeader = regexp.MustCompile(`^([IWEF])(\d{4} \d{2}:\d{2}:\d{2}\.\d+)\s+\d+\s+[^\]]+\]\s+(.*)$`)
+
+func parseKlogLine(raw string, year int) (klogLine, bool) {
+	match := klogHeader.FindStringSubmatch(raw)
+	if match == nil {
+		return klogLine{}, false
+	}
+	ts, err := time.Parse("2006 0102 15:04:05.000000", strconv.Itoa(year)+" "+match[2])
+	if err != nil {
+		return klogLine{}, false
+	}
+	body := match[3]
+	line := klogLine{Severity: match[1], Time: ts, Fields: map[string]string{}}
+	if strings.HasPrefix(body, `"`) {
+		msg, rest, ok := readQuoted(body)
+		if !ok {
+			return klogLine{}, false
+		}
+		line.Message = msg
+		line.Fields = parseKeyValues(rest)
+	} else {
+		line.Message = body
+	}
+	return line, true
+}
+
+// parseKeyValues splits klog structured key=value pairs. Values are either Go
+// quoted strings or bare tokens that may nest {...}, [...] or map[...].
+func parseKeyValues(input string) map[string]string {
+	fields := map[string]string{}
+	rest := strings.TrimSpace(input)
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			break
+		}
+		key := rest[:eq]
+		rest = rest[eq+1:]
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			var ok bool
+			value, rest, ok = readQuoted(rest)
+			if !ok {
+				break
+			}
+		} else {
+			value, rest = readBare(rest)
+		}
+		fields[key] = value
+		rest = strings.TrimSpace(rest)
+	}
+	return fields
+}
+
+func readQuoted(input string) (string, string, bool) {
+	escaped := false
+	for i := 1; i < len(input); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case input[i] == '\\':
+			escaped = true
+		case input[i] == '"':
+			value, err := strconv.Unquote(input[:i+1])
+			if err != nil {
+				return "", "", false
+			}
+			return value, input[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+func readBare(input string) (string, string) {
+	depth := 0
+	for i := 0; i < len(input); i++ {
+		switch input[i] {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth > 0 {
+				depth--
+			}
+		case ' ':
+			if depth == 0 {
+				return input[:i], input[i:]
+			}
+		}
+	}
+	return input, ""
+}
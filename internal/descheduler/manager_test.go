@@ -2,7 +2,10 @@ package descheduler
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -49,6 +52,7 @@ func TestEnsureInstalledCreatesResources(t *testing.T) {
 		Namespace:  "deschedbench-test",
 		Image:      "registry.k8s.io/descheduler/descheduler:v0.32.2",
 		PolicyYAML: policy,
+		RunID:      "run-a",
 	}
 
 	if err := EnsureInstalled(ctx, client, cfg); err != nil {
@@ -58,16 +62,31 @@ func TestEnsureInstalledCreatesResources(t *testing.T) {
 	if _, err := client.CoreV1().ServiceAccounts(cfg.Namespace).Get(ctx, "deschedbench-descheduler", metav1.GetOptions{}); err != nil {
 		t.Fatalf("service account missing: %v", err)
 	}
-	if _, err := client.RbacV1().ClusterRoles().Get(ctx, "deschedbench-descheduler", metav1.GetOptions{}); err != nil {
+	if _, err := client.RbacV1().ClusterRoles().Get(ctx, "deschedbench-descheduler-run-a", metav1.GetOptions{}); err != nil {
 		t.Fatalf("cluster role missing: %v", err)
 	}
-	binding, err := client.RbacV1().ClusterRoleBindings().Get(ctx, "deschedbench-descheduler", metav1.GetOptions{})
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(ctx, "deschedbench-descheduler-run-a", metav1.GetOptions{})
 	if err != nil {
 		t.Fatalf("cluster role binding missing: %v", err)
 	}
 	if len(binding.Subjects) == 0 || binding.Subjects[0].Namespace != cfg.Namespace {
 		t.Fatalf("cluster role binding subject namespace mismatch")
 	}
+	if binding.RoleRef.Name != "deschedbench-descheduler-run-a" {
+		t.Fatalf("cluster role binding refers to %q", binding.RoleRef.Name)
+	}
+
+	// A second run gets its own RBAC and leaves the first run's binding alone.
+	other := cfg
+	other.Namespace = "deschedbench-other"
+	other.RunID = "run-b"
+	if err := EnsureInstalled(ctx, client, other); err != nil {
+		t.Fatalf("EnsureInstalled for a second run failed: %v", err)
+	}
+	binding, err = client.RbacV1().ClusterRoleBindings().Get(ctx, "deschedbench-descheduler-run-a", metav1.GetOptions{})
+	if err != nil || binding.Subjects[0].Namespace != cfg.Namespace {
+		t.Fatalf("expected the first run's binding unchanged, got %+v (err %v)", binding, err)
+	}
 	cm, err := client.CoreV1().ConfigMaps(cfg.Namespace).Get(ctx, "deschedbench-policy", metav1.GetOptions{})
 	if err != nil {
 		t.Fatalf("configmap missing: %v", err)
@@ -105,3 +124,247 @@ func TestRunOnceCreatesJob(t *testing.T) {
 		t.Fatalf("job image mismatch: %s", job.Spec.Template.Spec.Containers[0].Image)
 	}
 }
+
+func TestStartDeploymentMode(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-test"}})
+
+	cfg := Config{
+		Namespace:  "deschedbench-test",
+		Image:      "registry.k8s.io/descheduler/descheduler:v0.32.2",
+		PolicyYAML: "apiVersion: descheduler/v1alpha2\nkind: DeschedulerPolicy\n",
+		Mode:       ModeDeployment,
+		Interval:   30 * time.Second,
+	}
+
+	if err := Start(ctx, client, cfg); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	dep, err := client.AppsV1().Deployments(cfg.Namespace).Get(ctx, "deschedbench-descheduler", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("deployment missing: %v", err)
+	}
+	args := strings.Join(dep.Spec.Template.Spec.Containers[0].Args, " ")
+	if !strings.Contains(args, "--descheduling-interval=30s") {
+		t.Fatalf("expected descheduling interval arg, got %q", args)
+	}
+}
+
+func TestStartCronJobMode(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-test"}})
+
+	cfg := Config{
+		Namespace:    "deschedbench-test",
+		Image:        "registry.k8s.io/descheduler/descheduler:v0.32.2",
+		PolicyYAML:   "apiVersion: descheduler/v1alpha2\nkind: DeschedulerPolicy\n",
+		Mode:         ModeCronJob,
+		CronSchedule: "*/2 * * * *",
+	}
+
+	if err := Start(ctx, client, cfg); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	cronJob, err := client.BatchV1().CronJobs(cfg.Namespace).Get(ctx, "deschedbench-descheduler", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("cronjob missing: %v", err)
+	}
+	if cronJob.Spec.Schedule != cfg.CronSchedule {
+		t.Fatalf("cronjob schedule mismatch: %s", cronJob.Spec.Schedule)
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	raw := `# HELP descheduler_pods_evicted Number of evicted pods
+# TYPE descheduler_pods_evicted counter
+descheduler_pods_evicted{namespace="ns",node="n1",profile="deschedbench",result="success",strategy="LowNodeUtilization"} 3
+descheduler_pods_evicted{namespace="ns",node="n2",profile="deschedbench",result="success",strategy="LowNodeUtilization"} 2
+descheduler_pods_evicted{namespace="ns",node="n2",profile="deschedbench",result="error",strategy="RemoveDuplicates"} 1
+# HELP descheduler_descheduler_loop_duration_seconds Time taken to complete a full descheduling cycle
+# TYPE descheduler_descheduler_loop_duration_seconds histogram
+descheduler_descheduler_loop_duration_seconds_bucket{le="0.1"} 0
+descheduler_descheduler_loop_duration_seconds_bucket{le="1"} 1
+descheduler_descheduler_loop_duration_seconds_bucket{le="+Inf"} 1
+descheduler_descheduler_loop_duration_seconds_sum 0.42
+descheduler_descheduler_loop_duration_seconds_count 1
+`
+	metrics, err := ParseMetrics([]byte(raw))
+	if err != nil {
+		t.Fatalf("ParseMetrics failed: %v", err)
+	}
+	if len(metrics.PodsEvicted) != 3 {
+		t.Fatalf("expected 3 eviction series, got %d", len(metrics.PodsEvicted))
+	}
+	byStrategy := metrics.EvictionsByStrategy()
+	if byStrategy["LowNodeUtilization"] != 5 {
+		t.Fatalf("expected 5 LowNodeUtilization evictions, got %v", byStrategy["LowNodeUtilization"])
+	}
+	if _, ok := byStrategy["RemoveDuplicates"]; ok {
+		t.Fatalf("expected failed evictions to be excluded")
+	}
+	if metrics.LoopDuration == nil || metrics.LoopDuration.Count != 1 || len(metrics.LoopDuration.Buckets) != 2 {
+		t.Fatalf("unexpected loop duration: %+v", metrics.LoopDuration)
+	}
+}
+
+func TestParseLogs(t *testing.T) {
+	logs := `I0209 02:50:25.100000       1 nodeutilization.go:204] "Node is underutilized" node="m02" usage={"cpu":"100m","pods":"2"} usagePercentage={"cpu":5,"pods":1}
+I0209 02:50:25.100100       1 nodeutilization.go:210] "Node is overutilized" node="m03" usage=map[cpu:2 pods:40] usagePercentage=map[cpu:90 pods:40]
+I0209 02:50:25.100200       1 nodeutilization.go:207] "Node is appropriately utilized" node="m04"
+I0209 02:50:25.200000       1 evictions.go:551] "Evicted pod" pod="ns/bench-small-abc" reason="" strategy="LowNodeUtilization" node="m03" profile="deschedbench"
+E0209 02:50:25.300000       1 evictions.go:497] "Error evicting pod" err="maximum number of evicted pods per node reached" limit=10 node="m03"
+I0209 02:50:25.400000       1 profile.go:321] "Total number of pods evicted" extension point="Balance" evictedPods=1
+not a klog line
+`
+	decisions := ParseLogs(strings.NewReader(logs), 2026)
+	if len(decisions.NodeClassifications) != 3 {
+		t.Fatalf("expected 3 node classifications, got %d", len(decisions.NodeClassifications))
+	}
+	if decisions.NodeClassifications[1].Class != NodeOverutilized || decisions.NodeClassifications[1].Usage != "map[cpu:2 pods:40]" {
+		t.Fatalf("unexpected classification: %+v", decisions.NodeClassifications[1])
+	}
+	if len(decisions.Evictions) != 1 || decisions.Evictions[0].Strategy != "LowNodeUtilization" || decisions.Evictions[0].Pod != "ns/bench-small-abc" {
+		t.Fatalf("unexpected evictions: %+v", decisions.Evictions)
+	}
+	if len(decisions.LimitHits) != 1 || decisions.LimitHits[0].Limit != "10" {
+		t.Fatalf("unexpected limit hits: %+v", decisions.LimitHits)
+	}
+	if decisions.TotalEvicted != 1 {
+		t.Fatalf("expected total evicted 1, got %d", decisions.TotalEvicted)
+	}
+	if decisions.LinesParsed != 6 {
+		t.Fatalf("expected 6 parsed lines, got %d", decisions.LinesParsed)
+	}
+}
+
+func TestWithPriorityThreshold(t *testing.T) {
+	policy := `apiVersion: "descheduler/v1alpha2"
+kind: "DeschedulerPolicy"
+profiles:
+  - name: "deschedbench"
+    pluginConfig:
+      - name: "DefaultEvictor"
+        args:
+          evictSystemCriticalPods: true
+      - name: "LowNodeUtilization"
+`
+	out, err := WithPriorityThreshold(policy, 100000)
+	if err != nil {
+		t.Fatalf("WithPriorityThreshold failed: %v", err)
+	}
+	if !strings.Contains(out, "value: 100000") || !strings.Contains(out, "evictSystemCriticalPods: false") {
+		t.Fatalf("unexpected policy:\n%s", out)
+	}
+	if _, err := WithPriorityThreshold("profiles: []\n", 1000); err == nil {
+		t.Fatal("expected error without a DefaultEvictor")
+	}
+}
+
+func TestMetricsSince(t *testing.T) {
+	evicted := func(node string, count float64) EvictedCount {
+		return EvictedCount{Strategy: "LowNodeUtilization", Node: node, Namespace: "ns", Result: "success", Count: count}
+	}
+	hist := func(count uint64, sum float64, bucket uint64) *Histogram {
+		return &Histogram{Count: count, Sum: sum, Buckets: []HistogramBucket{{UpperBound: 1, Count: bucket}}}
+	}
+	prev := Metrics{Source: "pod/a", PodsEvicted: []EvictedCount{evicted("n1", 3)}, LoopDuration: hist(1, 0.5, 1)}
+	cur := Metrics{Source: "pod/a", PodsEvicted: []EvictedCount{evicted("n1", 5), evicted("n2", 1)}, LoopDuration: hist(3, 1.5, 2)}
+
+	delta := cur.Since(prev)
+	if got := delta.EvictionsByStrategy()["LowNodeUtilization"]; got != 3 {
+		t.Fatalf("expected 3 evictions since the previous scrape, got %v", got)
+	}
+	if delta.LoopDuration.Count != 2 || delta.LoopDuration.Sum != 1 || delta.LoopDuration.Buckets[0].Count != 1 {
+		t.Fatalf("unexpected loop duration delta: %+v", delta.LoopDuration)
+	}
+	if cur.LoopDuration.Count != 3 {
+		t.Fatalf("expected Since to leave the scrape unchanged")
+	}
+
+	restarted := Metrics{Source: "pod/b", PodsEvicted: []EvictedCount{evicted("n1", 1)}}
+	if got := restarted.Since(cur).EvictionsByStrategy()["LowNodeUtilization"]; got != 1 {
+		t.Fatalf("expected a new pod to keep its own totals, got %v", got)
+	}
+}
+
+func TestCollectKeepsScrapeAfterLastEviction(t *testing.T) {
+	evicted := func(count float64) []EvictedCount {
+		return []EvictedCount{{Strategy: "LowNodeUtilization", Node: "n1", Namespace: "ns", Result: "success", Count: count}}
+	}
+	scrapeOf := func(metrics Metrics) podScrape {
+		metrics.Source = "pod/job-a"
+		return podScrape{metrics: map[string]Metrics{metrics.Source: metrics}}
+	}
+	passes := []podScrape{
+		{starting: true},
+		scrapeOf(Metrics{PodsEvicted: evicted(1)}),
+		scrapeOf(Metrics{PodsEvicted: evicted(3)}),
+		// The loop finished after its last eviction; the pod exits next.
+		scrapeOf(Metrics{PodsEvicted: evicted(5), LoopDuration: &Histogram{Count: 1, Sum: 0.4}}),
+		// A pod shutting down answers with empty counters.
+		scrapeOf(Metrics{}),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan struct{})
+	calls := 0
+	scrape := func(context.Context) (podScrape, error) {
+		if calls == len(passes) {
+			return podScrape{}, errors.New("pod gone")
+		}
+		pass := passes[calls]
+		calls++
+		if calls == 4 {
+			close(done)
+		}
+		return pass, nil
+	}
+	// The regular interval never fires: only the fast polling of a starting
+	// or looping pod reaches the final scrape.
+	got, err := collect(ctx, done, time.Hour, time.Millisecond, scrape)
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if len(got) != 1 || !got[0].Looped() || got[0].EvictionsByStrategy()["LowNodeUtilization"] != 5 {
+		t.Fatalf("expected the scrape taken after the last eviction, got %+v", got)
+	}
+
+	if _, err := collect(ctx, closedChan(), time.Hour, time.Millisecond, func(context.Context) (podScrape, error) {
+		return podScrape{}, errors.New("no running descheduler pod")
+	}); err == nil {
+		t.Fatalf("expected an error when no pod was scraped")
+	}
+}
+
+func TestMergeMetrics(t *testing.T) {
+	evicted := func(node string, count float64) EvictedCount {
+		return EvictedCount{Strategy: "LowNodeUtilization", Node: node, Namespace: "ns", Result: "success", Count: count}
+	}
+	hist := func(count uint64, sum float64) *Histogram {
+		return &Histogram{Count: count, Sum: sum, Buckets: []HistogramBucket{{UpperBound: 1, Count: count}}}
+	}
+	a := Metrics{Source: "pod/cron-1", PodsEvicted: []EvictedCount{evicted("n1", 2)}, LoopDuration: hist(1, 0.5)}
+	b := Metrics{Source: "pod/cron-2", PodsEvicted: []EvictedCount{evicted("n1", 1), evicted("n2", 4)}, LoopDuration: hist(1, 0.25)}
+
+	merged := MergeMetrics([]Metrics{a, b})
+	if merged.Source != "pod/cron-1,pod/cron-2" {
+		t.Fatalf("unexpected source %q", merged.Source)
+	}
+	if got := merged.EvictionsByStrategy()["LowNodeUtilization"]; got != 7 || len(merged.PodsEvicted) != 2 {
+		t.Fatalf("expected 7 evictions over 2 series, got %v in %+v", got, merged.PodsEvicted)
+	}
+	if merged.LoopDuration.Count != 2 || merged.LoopDuration.Sum != 0.75 || merged.LoopDuration.Buckets[0].Count != 2 {
+		t.Fatalf("unexpected loop duration: %+v", merged.LoopDuration)
+	}
+	if a.LoopDuration.Count != 1 || a.PodsEvicted[0].Count != 2 {
+		t.Fatalf("expected MergeMetrics to leave its inputs unchanged")
+	}
+}
+
+func closedChan() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
 var baseManifestFiles = []string{
@@ -15,7 +16,16 @@ var baseManifestFiles = []string{
 	"deploy/descheduler/manifests/metrics-service.yaml",
 }
 
-const jobManifestFile = "deploy/descheduler/manifests/job.yaml"
+const (
+	jobManifestFile        = "deploy/descheduler/manifests/job.yaml"
+	cronJobManifestFile    = "deploy/descheduler/manifests/cronjob.yaml"
+	deploymentManifestFile = "deploy/descheduler/manifests/deployment.yaml"
+)
+
+const (
+	defaultSchedule = "*/1 * * * *"
+	defaultInterval = time.Minute
+)
 
 func renderBaseManifests(cfg Config) ([]string, error) {
 	if cfg.Namespace == "" {
@@ -24,11 +34,21 @@ func renderBaseManifests(cfg Config) ([]string, error) {
 	if cfg.PolicyYAML == "" {
 		return nil, fmt.Errorf("policy YAML is required")
 	}
-	schedule := cfg.CronSchedule
-	if schedule == "" {
-		schedule = "*/1 * * * *"
+	return renderManifestSet(cfg, baseManifestFiles)
+}
+
+// RenderManifests returns every manifest a run applies for cfg: the shared
+// objects followed by the Job, CronJob or Deployment for cfg.Mode.
+func RenderManifests(cfg Config) ([]string, error) {
+	manifests, err := renderBaseManifests(cfg)
+	if err != nil {
+		return nil, err
 	}
-	return renderManifestSet(cfg, baseManifestFiles, schedule)
+	workload, err := renderModeManifest(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return append(manifests, workload), nil
 }
 
 func indentPolicy(policy string, spaces int) string {
@@ -45,28 +65,49 @@ func indentPolicy(policy string, spaces int) string {
 }
 
 func renderJobManifest(cfg Config) (string, error) {
+	return renderWorkloadManifest(cfg, jobManifestFile)
+}
+
+func renderModeManifest(cfg Config) (string, error) {
+	switch cfg.Mode {
+	case ModeCronJob:
+		return renderWorkloadManifest(cfg, cronJobManifestFile)
+	case ModeDeployment:
+		return renderWorkloadManifest(cfg, deploymentManifestFile)
+	case ModeJob, "":
+		return renderWorkloadManifest(cfg, jobManifestFile)
+	default:
+		return "", ValidateMode(cfg.Mode)
+	}
+}
+
+func renderWorkloadManifest(cfg Config, path string) (string, error) {
 	if cfg.Namespace == "" {
 		return "", fmt.Errorf("descheduler namespace is required")
 	}
 	if cfg.PolicyYAML == "" {
 		return "", fmt.Errorf("policy YAML is required")
 	}
-	schedule := cfg.CronSchedule
-	if schedule == "" {
-		schedule = "*/1 * * * *"
-	}
-	manifests, err := renderManifestSet(cfg, []string{jobManifestFile}, schedule)
+	manifests, err := renderManifestSet(cfg, []string{path})
 	if err != nil {
 		return "", err
 	}
 	return manifests[0], nil
 }
 
-func renderManifestSet(cfg Config, paths []string, schedule string) ([]string, error) {
+func renderManifestSet(cfg Config, paths []string) ([]string, error) {
 	jobName := cfg.JobName
 	if jobName == "" {
 		jobName = "deschedbench-descheduler"
 	}
+	schedule := cfg.CronSchedule
+	if schedule == "" {
+		schedule = defaultSchedule
+	}
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
 	manifests := make([]string, 0, len(paths))
 	for _, path := range paths {
 		data, err := os.ReadFile(resolveManifestPath(path))
@@ -77,7 +118,10 @@ func renderManifestSet(cfg Config, paths []string, schedule string) ([]string, e
 		manifest = strings.ReplaceAll(manifest, "{{NAMESPACE}}", cfg.Namespace)
 		manifest = strings.ReplaceAll(manifest, "{{IMAGE}}", cfg.Image)
 		manifest = strings.ReplaceAll(manifest, "{{SCHEDULE}}", schedule)
+		manifest = strings.ReplaceAll(manifest, "{{INTERVAL}}", interval.String())
 		manifest = strings.ReplaceAll(manifest, "{{JOB_NAME}}", jobName)
+		manifest = strings.ReplaceAll(manifest, "{{RUN_ID}}", cfg.RunID)
+		manifest = strings.ReplaceAll(manifest, "{{RBAC_NAME}}", RBACName(cfg.RunID))
 		manifest = strings.ReplaceAll(manifest, "{{POLICY_YAML}}", indentPolicy(cfg.PolicyYAML, 4))
 		manifests = append(manifests, manifest)
 	}
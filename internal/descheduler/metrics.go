@@ -0,0 +1,499 @@
+package descheduler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	metricsPort = "10258"
+	metricsPath = "/metrics"
+)
+
+type Metrics struct {
+	Time             time.Time           `json:"time"`
+	Source           string              `json:"source"`
+	PodsEvicted      []EvictedCount      `json:"pods_evicted"`
+	LoopDuration     *Histogram          `json:"loop_duration,omitempty"`
+	StrategyDuration []StrategyHistogram `json:"strategy_duration,omitempty"`
+}
+
+type EvictedCount struct {
+	Strategy  string  `json:"strategy"`
+	Profile   string  `json:"profile,omitempty"`
+	Node      string  `json:"node"`
+	Namespace string  `json:"namespace"`
+	Result    string  `json:"result"`
+	Count     float64 `json:"count"`
+}
+
+type Histogram struct {
+	Count   uint64            `json:"count"`
+	Sum     float64           `json:"sum"`
+	Buckets []HistogramBucket `json:"buckets"`
+}
+
+type HistogramBucket struct {
+	UpperBound float64 `json:"upper_bound"`
+	Count      uint64  `json:"count"`
+}
+
+type StrategyHistogram struct {
+	Strategy  string    `json:"strategy"`
+	Profile   string    `json:"profile,omitempty"`
+	Histogram Histogram `json:"histogram"`
+}
+
+// fastScrapeInterval is how often a descheduler pod is scraped while it is
+// starting or inside its first loop.
+const fastScrapeInterval = 200 * time.Millisecond
+
+// MetricsCollector scrapes every running descheduler pod in the background
+// and keeps the newest scrape of each.
+type MetricsCollector struct {
+	done   chan struct{}
+	result chan collected
+}
+
+type collected struct {
+	metrics []Metrics
+	err     error
+}
+
+// podScrape is one pass over the descheduler pods.
+type podScrape struct {
+	metrics map[string]Metrics
+	// starting is set while a pod is not running yet: its whole loop may
+	// fit between two regular scrapes.
+	starting bool
+}
+
+// StartMetricsCollector scrapes the descheduler pods every interval until
+// Stop is called. One-shot Job and CronJob pods exit right after their loop,
+// so a pod that is starting or has not finished a loop yet is scraped every
+// fastScrapeInterval instead, to catch its final counters.
+func StartMetricsCollector(ctx context.Context, client kubernetes.Interface, namespace string, interval time.Duration) *MetricsCollector {
+	return startCollector(ctx, interval, fastScrapeInterval, func(ctx context.Context) (podScrape, error) {
+		return scrapePods(ctx, client, namespace)
+	})
+}
+
+func startCollector(ctx context.Context, interval, fast time.Duration, scrape func(context.Context) (podScrape, error)) *MetricsCollector {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	c := &MetricsCollector{done: make(chan struct{}), result: make(chan collected, 1)}
+	go func() {
+		metrics, err := collect(ctx, c.done, interval, fast, scrape)
+		c.result <- collected{metrics: metrics, err: err}
+	}()
+	return c
+}
+
+// Stop takes a last scrape and returns the newest scrape of every pod seen,
+// ordered by source. It fails when no pod could be scraped at all.
+func (c *MetricsCollector) Stop() ([]Metrics, error) {
+	close(c.done)
+	out := <-c.result
+	return out.metrics, out.err
+}
+
+func collect(ctx context.Context, done <-chan struct{}, interval, fast time.Duration, scrape func(context.Context) (podScrape, error)) ([]Metrics, error) {
+	latest := map[string]Metrics{}
+	var lastErr error
+	record := func() time.Duration {
+		next := interval
+		pass, err := scrape(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				lastErr = err
+			}
+			return next
+		}
+		if pass.starting {
+			next = fast
+		}
+		for source, metrics := range pass.metrics {
+			// A pod shutting down can answer with empty counters; they
+			// must not replace what it reported before.
+			if prev, ok := latest[source]; ok && metrics.empty() && !prev.empty() {
+				continue
+			}
+			latest[source] = metrics
+			if !metrics.Looped() {
+				next = fast
+			}
+		}
+		return next
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return collectedMetrics(latest, lastErr)
+		case <-done:
+			record()
+			return collectedMetrics(latest, lastErr)
+		case <-timer.C:
+			timer.Reset(record())
+		}
+	}
+}
+
+func collectedMetrics(latest map[string]Metrics, lastErr error) ([]Metrics, error) {
+	if len(latest) == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no descheduler metrics scraped")
+		}
+		return nil, lastErr
+	}
+	out := make([]Metrics, 0, len(latest))
+	for _, metrics := range latest {
+		out = append(out, metrics)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Source < out[j].Source })
+	return out, nil
+}
+
+// scrapePods reads the metrics endpoint of every running descheduler pod
+// through the apiserver proxy. It fails when no pod answered and none is
+// starting.
+func scrapePods(ctx context.Context, client kubernetes.Interface, namespace string) (podScrape, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=deschedbench-descheduler",
+	})
+	if err != nil {
+		return podScrape{}, fmt.Errorf("list descheduler pods: %w", err)
+	}
+	out := podScrape{metrics: map[string]Metrics{}}
+	var lastErr error
+	for _, pod := range pods.Items {
+		switch pod.Status.Phase {
+		case corev1.PodPending:
+			out.starting = true
+			continue
+		case corev1.PodRunning:
+		default:
+			continue
+		}
+		source := "pod/" + pod.Name
+		raw, err := client.CoreV1().Pods(namespace).ProxyGet("https", pod.Name, metricsPort, metricsPath, nil).DoRaw(ctx)
+		if err != nil {
+			lastErr = fmt.Errorf("scrape descheduler metrics via %s: %w", source, err)
+			continue
+		}
+		metrics, err := ParseMetrics(raw)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		metrics.Source = source
+		out.metrics[source] = metrics
+	}
+	if len(out.metrics) > 0 || out.starting {
+		return out, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no running descheduler pod in %s", namespace)
+	}
+	return out, lastErr
+}
+
+// ParseMetrics extracts eviction counters and loop duration histograms from a
+// Prometheus text exposition.
+func ParseMetrics(raw []byte) (Metrics, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(raw))
+	if err != nil {
+		return Metrics{}, fmt.Errorf("parse descheduler metrics: %w", err)
+	}
+
+	out := Metrics{Time: time.Now()}
+	for name, family := range families {
+		switch {
+		case name == "descheduler_pods_evicted":
+			for _, metric := range family.GetMetric() {
+				labels := labelMap(metric)
+				out.PodsEvicted = append(out.PodsEvicted, EvictedCount{
+					Strategy:  labels["strategy"],
+					Profile:   labels["profile"],
+					Node:      labels["node"],
+					Namespace: labels["namespace"],
+					Result:    labels["result"],
+					Count:     metricValue(metric),
+				})
+			}
+		case isDeschedulerMetric(name, "loop_duration_seconds"):
+			for _, metric := range family.GetMetric() {
+				if h := metric.GetHistogram(); h != nil {
+					hist := toHistogram(h)
+					out.LoopDuration = &hist
+				}
+			}
+		case isDeschedulerMetric(name, "strategy_duration_seconds"):
+			for _, metric := range family.GetMetric() {
+				h := metric.GetHistogram()
+				if h == nil {
+					continue
+				}
+				labels := labelMap(metric)
+				out.StrategyDuration = append(out.StrategyDuration, StrategyHistogram{
+					Strategy:  labels["strategy"],
+					Profile:   labels["profile"],
+					Histogram: toHistogram(h),
+				})
+			}
+		}
+	}
+
+	out.sort()
+	return out, nil
+}
+
+func (m *Metrics) sort() {
+	sort.Slice(m.PodsEvicted, func(i, j int) bool {
+		a, b := m.PodsEvicted[i], m.PodsEvicted[j]
+		if a.Strategy != b.Strategy {
+			return a.Strategy < b.Strategy
+		}
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		return a.Namespace < b.Namespace
+	})
+	sort.Slice(m.StrategyDuration, func(i, j int) bool {
+		return m.StrategyDuration[i].Strategy < m.StrategyDuration[j].Strategy
+	})
+}
+
+// Looped reports whether the scrape saw a finished descheduling loop. The
+// counters of a one-shot pod are final from then on.
+func (m Metrics) Looped() bool {
+	return m.LoopDuration != nil && m.LoopDuration.Count > 0
+}
+
+func (m Metrics) empty() bool {
+	return len(m.PodsEvicted) == 0 && len(m.StrategyDuration) == 0 && !m.Looped()
+}
+
+// MergeMetrics adds up the scrapes of several pods, such as the Jobs a
+// CronJob spawned during one window. Their sources are joined with commas.
+func MergeMetrics(scrapes []Metrics) Metrics {
+	if len(scrapes) == 1 {
+		return scrapes[0]
+	}
+	type key struct{ strategy, profile, node, namespace, result string }
+	var out Metrics
+	sources := make([]string, 0, len(scrapes))
+	evicted := map[key]int{}
+	strategies := map[string]int{}
+	for _, scrape := range scrapes {
+		sources = append(sources, scrape.Source)
+		if scrape.Time.After(out.Time) {
+			out.Time = scrape.Time
+		}
+		for _, c := range scrape.PodsEvicted {
+			k := key{c.Strategy, c.Profile, c.Node, c.Namespace, c.Result}
+			if i, ok := evicted[k]; ok {
+				out.PodsEvicted[i].Count += c.Count
+				continue
+			}
+			evicted[k] = len(out.PodsEvicted)
+			out.PodsEvicted = append(out.PodsEvicted, c)
+		}
+		if scrape.LoopDuration != nil {
+			var sum Histogram
+			if out.LoopDuration != nil {
+				sum = *out.LoopDuration
+			}
+			sum = sum.add(*scrape.LoopDuration)
+			out.LoopDuration = &sum
+		}
+		for _, s := range scrape.StrategyDuration {
+			k := s.Strategy + "/" + s.Profile
+			if i, ok := strategies[k]; ok {
+				out.StrategyDuration[i].Histogram = out.StrategyDuration[i].Histogram.add(s.Histogram)
+				continue
+			}
+			strategies[k] = len(out.StrategyDuration)
+			s.Histogram = Histogram{}.add(s.Histogram)
+			out.StrategyDuration = append(out.StrategyDuration, s)
+		}
+	}
+	out.Source = strings.Join(sources, ",")
+	out.sort()
+	return out
+}
+
+// EvictionsByStrategy sums successful eviction counters per strategy.
+func (m Metrics) EvictionsByStrategy() map[string]float64 {
+	out := map[string]float64{}
+	for _, count := range m.PodsEvicted {
+		if count.Result != "" && count.Result != "success" {
+			continue
+		}
+		out[count.Strategy] += count.Count
+	}
+	return out
+}
+
+// Since returns the counters and histograms accumulated after prev, for a
+// long-running descheduler whose metrics are cumulative since it started. A
+// scrape of another pod, or a counter that went down, means the process
+// restarted, so m is returned unchanged.
+func (m Metrics) Since(prev Metrics) Metrics {
+	if prev.Source != m.Source {
+		return m
+	}
+	type key struct{ strategy, profile, node, namespace, result string }
+	before := make(map[key]float64, len(prev.PodsEvicted))
+	for _, c := range prev.PodsEvicted {
+		before[key{c.Strategy, c.Profile, c.Node, c.Namespace, c.Result}] = c.Count
+	}
+	out := m
+	out.PodsEvicted = nil
+	for _, c := range m.PodsEvicted {
+		prior := before[key{c.Strategy, c.Profile, c.Node, c.Namespace, c.Result}]
+		if c.Count < prior {
+			return m
+		}
+		if c.Count > prior {
+			c.Count -= prior
+			out.PodsEvicted = append(out.PodsEvicted, c)
+		}
+	}
+	if m.LoopDuration != nil && prev.LoopDuration != nil {
+		hist, ok := m.LoopDuration.since(*prev.LoopDuration)
+		if !ok {
+			return m
+		}
+		out.LoopDuration = &hist
+	}
+	priorStrategies := make(map[string]Histogram, len(prev.StrategyDuration))
+	for _, s := range prev.StrategyDuration {
+		priorStrategies[s.Strategy+"/"+s.Profile] = s.Histogram
+	}
+	out.StrategyDuration = nil
+	for _, s := range m.StrategyDuration {
+		if prior, found := priorStrategies[s.Strategy+"/"+s.Profile]; found {
+			hist, ok := s.Histogram.since(prior)
+			if !ok {
+				return m
+			}
+			s.Histogram = hist
+		}
+		out.StrategyDuration = append(out.StrategyDuration, s)
+	}
+	return out
+}
+
+// since subtracts prev bucket by bucket. It reports false when h holds fewer
+// observations than prev or the buckets differ.
+func (h Histogram) since(prev Histogram) (Histogram, bool) {
+	if h.Count < prev.Count || len(h.Buckets) != len(prev.Buckets) {
+		return h, false
+	}
+	out := Histogram{Count: h.Count - prev.Count, Sum: h.Sum - prev.Sum}
+	for i, bucket := range h.Buckets {
+		if bucket.UpperBound != prev.Buckets[i].UpperBound || bucket.Count < prev.Buckets[i].Count {
+			return h, false
+		}
+		out.Buckets = append(out.Buckets, HistogramBucket{UpperBound: bucket.UpperBound, Count: bucket.Count - prev.Buckets[i].Count})
+	}
+	return out, true
+}
+
+// add sums two histograms into a new one. Buckets are summed only when
+// both have the same bounds; an empty h takes the bounds of other.
+func (h Histogram) add(other Histogram) Histogram {
+	out := Histogram{Count: h.Count + other.Count, Sum: h.Sum + other.Sum}
+	if len(h.Buckets) == 0 && h.Count == 0 {
+		out.Buckets = append([]HistogramBucket(nil), other.Buckets...)
+		return out
+	}
+	if len(h.Buckets) != len(other.Buckets) {
+		return out
+	}
+	for i, bucket := range h.Buckets {
+		if bucket.UpperBound != other.Buckets[i].UpperBound {
+			return Histogram{Count: out.Count, Sum: out.Sum}
+		}
+		out.Buckets = append(out.Buckets, HistogramBucket{UpperBound: bucket.UpperBound, Count: bucket.Count + other.Buckets[i].Count})
+	}
+	return out
+}
+
+// isDeschedulerMetric matches both the current descheduler_<suffix> name and
+// the older descheduler_descheduler_<suffix> name.
+func isDeschedulerMetric(name, suffix string) bool {
+	return name == "descheduler_"+suffix || name == "descheduler_descheduler_"+suffix
+}
+
+func labelMap(metric *dto.Metric) map[string]string {
+	out := make(map[string]string, len(metric.GetLabel()))
+	for _, pair := range metric.GetLabel() {
+		out[pair.GetName()] = pair.GetValue()
+	}
+	return out
+}
+
+func metricValue(metric *dto.Metric) float64 {
+	switch {
+	case metric.GetCounter() != nil:
+		return metric.GetCounter().GetValue()
+	case metric.GetGauge() != nil:
+		return metric.GetGauge().GetValue()
+	case metric.GetUntyped() != nil:
+		return metric.GetUntyped().GetValue()
+	default:
+		return 0
+	}
+}
+
+func toHistogram(h *dto.Histogram) Histogram {
+	out := Histogram{
+		Count: h.GetSampleCount(),
+		Sum:   h.GetSampleSum(),
+	}
+	for _, bucket := range h.GetBucket() {
+		// +Inf is implied by Count and cannot be encoded as JSON.
+		if math.IsInf(bucket.GetUpperBound(), 1) {
+			continue
+		}
+		out.Buckets = append(out.Buckets, HistogramBucket{
+			UpperBound: bucket.GetUpperBound(),
+			Count:      bucket.GetCumulativeCount(),
+		})
+	}
+	return out
+}
+
+// FormatEvictionsByStrategy renders per-strategy eviction counts for logs.
+func FormatEvictionsByStrategy(counts map[string]float64) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%.0f", name, counts[name]))
+	}
+	return strings.Join(parts, " ")
+}
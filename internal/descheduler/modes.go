@@ -0,0 +1,18 @@
+package descheduler
+
+import "fmt"
+
+const (
+	ModeJob        = "job"
+	ModeCronJob    = "cronjob"
+	ModeDeployment = "deployment"
+)
+
+func ValidateMode(mode string) error {
+	switch mode {
+	case ModeJob, ModeCronJob, ModeDeployment:
+		return nil
+	default:
+		return fmt.Errorf("unknown descheduler mode %q (expected %s, %s or %s)", mode, ModeJob, ModeCronJob, ModeDeployment)
+	}
+}
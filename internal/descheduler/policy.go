@@ -0,0 +1,46 @@
+package descheduler
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// WithPriorityThreshold sets the DefaultEvictor priorityThreshold of every
+// profile in policyYAML, so pods with a priority of at least value are
+// never evicted. evictSystemCriticalPods is forced to false alongside:
+// with it set, the descheduler evicts pods of any priority.
+func WithPriorityThreshold(policyYAML string, value int32) (string, error) {
+	var policy map[string]any
+	if err := yaml.Unmarshal([]byte(policyYAML), &policy); err != nil {
+		return "", fmt.Errorf("parse policy: %w", err)
+	}
+	profiles, _ := policy["profiles"].([]any)
+	found := false
+	for _, p := range profiles {
+		profile, _ := p.(map[string]any)
+		configs, _ := profile["pluginConfig"].([]any)
+		for _, c := range configs {
+			config, _ := c.(map[string]any)
+			if config["name"] != "DefaultEvictor" {
+				continue
+			}
+			args, _ := config["args"].(map[string]any)
+			if args == nil {
+				args = map[string]any{}
+				config["args"] = args
+			}
+			args["priorityThreshold"] = map[string]any{"value": value}
+			args["evictSystemCriticalPods"] = false
+			found = true
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("policy has no DefaultEvictor plugin config")
+	}
+	out, err := yaml.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,83 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Annotation is a Grafana annotation. With TimeEnd set it is a region.
+type Annotation struct {
+	Time    time.Time
+	TimeEnd time.Time
+	Tags    []string
+	Text    string
+}
+
+// Client posts annotations to the Grafana HTTP API.
+type Client struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+// NewClient returns a client for the Grafana instance at url. token is a
+// service account token with annotation write access; it may be empty for
+// anonymous setups.
+func NewClient(url, token string) *Client {
+	return &Client{
+		url:   strings.TrimRight(url, "/"),
+		token: token,
+		http:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Create posts a to /api/annotations and returns the annotation ID.
+func (c *Client) Create(ctx context.Context, a Annotation) (int64, error) {
+	payload := struct {
+		Time    int64    `json:"time"`
+		TimeEnd int64    `json:"timeEnd,omitempty"`
+		Tags    []string `json:"tags"`
+		Text    string   `json:"text"`
+	}{
+		Time: a.Time.UnixMilli(),
+		Tags: a.Tags,
+		Text: a.Text,
+	}
+	if !a.TimeEnd.IsZero() {
+		payload.TimeEnd = a.TimeEnd.UnixMilli()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/api/annotations", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode/100 != 2 {
+		return 0, fmt.Errorf("grafana annotation: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(data, &created); err != nil {
+		return 0, fmt.Errorf("decode grafana response: %w", err)
+	}
+	return created.ID, nil
+}
@@ -0,0 +1,55 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreateRegion(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/annotations" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("unexpected authorization %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"id":42,"message":"Annotation added"}`))
+	}))
+	defer server.Close()
+
+	start := time.UnixMilli(1_700_000_000_000)
+	id, err := NewClient(server.URL+"/", "secret").Create(context.Background(), Annotation{
+		Time:    start,
+		TimeEnd: start.Add(90 * time.Second),
+		Tags:    []string{"deschedbench", "run_id:r1"},
+		Text:    "drain",
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if id != 42 {
+		t.Fatalf("expected id 42, got %d", id)
+	}
+	if got["time"] != float64(1_700_000_000_000) || got["timeEnd"] != float64(1_700_000_090_000) {
+		t.Fatalf("unexpected region bounds: %v", got)
+	}
+}
+
+func TestCreateReportsHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL, "").Create(context.Background(), Annotation{Time: time.Now()}); err == nil {
+		t.Fatalf("expected error for 401")
+	}
+}
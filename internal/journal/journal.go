@@ -0,0 +1,201 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/metrics"
+	"k8s-descheduler-benchmark/internal/report"
+)
+
+const (
+	// DefaultDir is where runs keep their journals unless told otherwise.
+	DefaultDir = "results/.journal"
+
+	StatusRunning   = "running"
+	StatusCleaned   = "cleaned"
+	StatusRecovered = "recovered"
+)
+
+// Journal is the on-disk record of a run in flight. It is rewritten at every
+// phase mark so that a run killed with SIGKILL can still be cleaned up and
+// summarized by `deschedbench recover`.
+type Journal struct {
+	RunID      string    `json:"run_id"`
+	Scenario   string    `json:"scenario"`
+	Profile    string    `json:"profile"`
+	Namespace  string    `json:"namespace"`
+	OutputPath string    `json:"output_path"`
+	Status     string    `json:"status"`
+	StartTime  time.Time `json:"start_time"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Phase      string    `json:"phase"`
+	// CordonedNodes are the nodes the run has cordoned and not restored
+	// yet. A node is added before its cordon is applied.
+	CordonedNodes []string `json:"cordoned_nodes"`
+	// Objects are the cluster objects the run creates, recorded before
+	// any of them is created.
+	Objects []Object             `json:"objects"`
+	Phases  []report.PhaseMarker `json:"phases"`
+	Samples []metrics.Sample     `json:"samples"`
+	// Config is the configuration the result file of the run records, so
+	// recovery can write a result of the same shape.
+	Config report.RunConfig `json:"config"`
+}
+
+// Object identifies something the run created in the cluster. Kind uses
+// the names of cleanup actions: namespace, priorityclass, clusterrole and
+// clusterrolebinding.
+type Object struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Writer serializes updates to a single journal file.
+type Writer struct {
+	path string
+
+	mu      sync.Mutex
+	journal Journal
+}
+
+// Path returns the journal file for runID inside dir.
+func Path(dir, runID string) string {
+	return filepath.Join(dir, runID+".json")
+}
+
+// Create writes the initial journal for a run and returns a writer for it.
+func Create(dir string, journal Journal) (*Writer, error) {
+	if journal.RunID == "" {
+		return nil, fmt.Errorf("journal run id is required")
+	}
+	if journal.Status == "" {
+		journal.Status = StatusRunning
+	}
+	w := &Writer{path: Path(dir, journal.RunID), journal: journal}
+	if err := w.Update(func(*Journal) {}); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) Path() string {
+	return w.path
+}
+
+// Update applies fn to the journal and persists it. The file is replaced
+// atomically so a crash mid-write leaves the previous version intact.
+func (w *Writer) Update(fn func(*Journal)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fn(&w.journal)
+	w.journal.UpdatedAt = time.Now()
+	return writeAtomic(w.path, w.journal)
+}
+
+// Cordoned records that node is about to be cordoned. It is written before
+// the cordon so the node is never missing from the journal.
+func (w *Writer) Cordoned(node string) error {
+	return w.Update(func(j *Journal) {
+		for _, name := range j.CordonedNodes {
+			if name == node {
+				return
+			}
+		}
+		j.CordonedNodes = append(j.CordonedNodes, node)
+	})
+}
+
+// Restored removes node from the cordoned set.
+func (w *Writer) Restored(node string) error {
+	return w.Update(func(j *Journal) {
+		out := j.CordonedNodes[:0]
+		for _, name := range j.CordonedNodes {
+			if name != node {
+				out = append(out, name)
+			}
+		}
+		j.CordonedNodes = out
+	})
+}
+
+// Load reads a journal file.
+func Load(path string) (Journal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Journal{}, err
+	}
+	var journal Journal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return Journal{}, fmt.Errorf("parse journal %s: %w", path, err)
+	}
+	return journal, nil
+}
+
+// Save overwrites the journal file for journal.RunID in dir.
+func Save(dir string, journal Journal) error {
+	journal.UpdatedAt = time.Now()
+	return writeAtomic(Path(dir, journal.RunID), journal)
+}
+
+// List returns every journal in dir, oldest run first.
+func List(dir string) ([]Journal, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Journal
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		journal, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, journal)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].StartTime.Before(out[j].StartTime)
+	})
+	return out, nil
+}
+
+func writeAtomic(path string, payload any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
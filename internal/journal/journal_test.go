@@ -0,0 +1,55 @@
+package journal
+
+import (
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/report"
+)
+
+func TestWriterPersistsUpdates(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Create(dir, Journal{
+		RunID:     "run-a",
+		Namespace: "deschedbench-run-a",
+		StartTime: time.Now(),
+		Config:    report.RunConfig{RunID: "run-a", Mix: "small=4"},
+	})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if err := w.Update(func(j *Journal) { j.Phase = "drain:start" }); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := w.Cordoned("w1"); err != nil {
+		t.Fatalf("cordoned failed: %v", err)
+	}
+	if err := w.Cordoned("w2"); err != nil {
+		t.Fatalf("cordoned failed: %v", err)
+	}
+	if err := w.Restored("w1"); err != nil {
+		t.Fatalf("restored failed: %v", err)
+	}
+
+	got, err := Load(Path(dir, "run-a"))
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if got.Status != StatusRunning || got.Phase != "drain:start" {
+		t.Fatalf("unexpected journal: %+v", got)
+	}
+	if len(got.CordonedNodes) != 1 || got.CordonedNodes[0] != "w2" {
+		t.Fatalf("expected only w2 still cordoned, got %v", got.CordonedNodes)
+	}
+	if got.Config.Mix != "small=4" {
+		t.Fatalf("expected the config persisted, got %+v", got.Config)
+	}
+
+	journals, err := List(dir)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if len(journals) != 1 || journals[0].RunID != "run-a" {
+		t.Fatalf("unexpected journals: %+v", journals)
+	}
+}
@@ -2,28 +2,85 @@ package k8s
 
 import (
 	"context"
+	"strconv"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-func CordonNode(ctx context.Context, client kubernetes.Interface, name string) error {
-	return updateNodeSchedulable(ctx, client, name, true)
+// CordonedByAnnotation records the run that cordoned a node, and
+// PreviousUnschedulableAnnotation the node's spec.unschedulable before that,
+// so cleanup can put back exactly what it changed.
+const (
+	CordonedByAnnotation            = "deschedbench/cordoned-by"
+	PreviousUnschedulableAnnotation = "deschedbench/previous-unschedulable"
+)
+
+// CordonNode marks the node unschedulable and records runID and the previous
+// state on it. Cordoning a node already cordoned by deschedbench keeps the
+// originally recorded state.
+func CordonNode(ctx context.Context, client kubernetes.Interface, name, runID string) error {
+	node, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, ok := node.Annotations[CordonedByAnnotation]; !ok {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[CordonedByAnnotation] = runID
+		node.Annotations[PreviousUnschedulableAnnotation] = strconv.FormatBool(node.Spec.Unschedulable)
+	}
+	node.Spec.Unschedulable = true
+	_, err = client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	return err
 }
 
+// UncordonNode makes the node schedulable regardless of who cordoned it.
 func UncordonNode(ctx context.Context, client kubernetes.Interface, name string) error {
-	return updateNodeSchedulable(ctx, client, name, false)
+	node, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	_, tracked := node.Annotations[CordonedByAnnotation]
+	if !node.Spec.Unschedulable && !tracked {
+		return nil
+	}
+	node.Spec.Unschedulable = false
+	clearCordonAnnotations(node)
+	_, err = client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	return err
 }
 
-func updateNodeSchedulable(ctx context.Context, client kubernetes.Interface, name string, unschedulable bool) error {
+// RestoreNode puts a node cordoned by CordonNode back into its recorded
+// schedulable state. Nodes without a deschedbench record are left alone.
+func RestoreNode(ctx context.Context, client kubernetes.Interface, name string) error {
 	node, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
-	if node.Spec.Unschedulable == unschedulable {
+	if _, ok := node.Annotations[CordonedByAnnotation]; !ok {
 		return nil
 	}
-	node.Spec.Unschedulable = unschedulable
+	previous, err := strconv.ParseBool(node.Annotations[PreviousUnschedulableAnnotation])
+	if err != nil {
+		previous = false
+	}
+	node.Spec.Unschedulable = previous
+	clearCordonAnnotations(node)
 	_, err = client.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
 	return err
 }
+
+// CordonedBy returns the run ID that cordoned node, and whether deschedbench
+// cordoned it at all.
+func CordonedBy(node corev1.Node) (string, bool) {
+	runID, ok := node.Annotations[CordonedByAnnotation]
+	return runID, ok
+}
+
+func clearCordonAnnotations(node *corev1.Node) {
+	delete(node.Annotations, CordonedByAnnotation)
+	delete(node.Annotations, PreviousUnschedulableAnnotation)
+}
@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCordonAndRestoreNode(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "w1"}})
+	if err := CordonNode(ctx, client, "w1", "run-a"); err != nil {
+		t.Fatalf("cordon failed: %v", err)
+	}
+	node, _ := client.CoreV1().Nodes().Get(ctx, "w1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Fatalf("expected node cordoned")
+	}
+	if runID, ok := CordonedBy(*node); !ok || runID != "run-a" {
+		t.Fatalf("expected cordon recorded for run-a, got %q", runID)
+	}
+	if node.Annotations[PreviousUnschedulableAnnotation] != "false" {
+		t.Fatalf("expected previous state recorded, got %q", node.Annotations[PreviousUnschedulableAnnotation])
+	}
+
+	if err := RestoreNode(ctx, client, "w1"); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	node, _ = client.CoreV1().Nodes().Get(ctx, "w1", metav1.GetOptions{})
+	if node.Spec.Unschedulable {
+		t.Fatalf("expected node restored to schedulable")
+	}
+	if _, ok := CordonedBy(*node); ok {
+		t.Fatalf("expected cordon annotations removed")
+	}
+}
+
+func TestRestoreNodeIgnoresForeignCordon(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "w1"},
+		Spec:       corev1.NodeSpec{Unschedulable: true},
+	})
+	if err := RestoreNode(ctx, client, "w1"); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	node, _ := client.CoreV1().Nodes().Get(ctx, "w1", metav1.GetOptions{})
+	if !node.Spec.Unschedulable {
+		t.Fatalf("expected operator cordon left in place")
+	}
+}
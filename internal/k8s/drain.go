@@ -19,6 +19,9 @@ type DrainOptions struct {
 	Timeout       time.Duration
 }
 
+// DrainNode evicts the pods of opts.Namespace from node name and waits until
+// they are gone. Like kubectl drain without --force, it leaves bare pods
+// alone: nothing would recreate them.
 func DrainNode(ctx context.Context, client kubernetes.Interface, name string, opts DrainOptions) error {
 	if opts.Namespace == "" {
 		return fmt.Errorf("namespace is required for drain")
@@ -34,7 +37,7 @@ func DrainNode(ctx context.Context, client kubernetes.Interface, name string, op
 		return err
 	}
 	for _, pod := range pods.Items {
-		if isMirrorPod(&pod) || isDaemonSetPod(&pod) {
+		if isMirrorPod(&pod) || isDaemonSetPod(&pod) || isBarePod(&pod) {
 			continue
 		}
 		eviction := &policyv1.Eviction{
@@ -58,7 +61,7 @@ func DrainNode(ctx context.Context, client kubernetes.Interface, name string, op
 			return false, err
 		}
 		for _, pod := range remaining.Items {
-			if isMirrorPod(&pod) || isDaemonSetPod(&pod) {
+			if isMirrorPod(&pod) || isDaemonSetPod(&pod) || isBarePod(&pod) {
 				continue
 			}
 			return false, nil
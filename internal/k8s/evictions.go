@@ -19,9 +19,21 @@ type EvictionRecord struct {
 	EvictedAt         time.Time `json:"evicted_at"`
 	RescheduledAt     time.Time `json:"rescheduled_at,omitempty"`
 	RescheduleSeconds float64   `json:"reschedule_seconds"`
+	// Kind is the workload kind of the evicted pod. Bare pods are never
+	// replaced, so their evictions always strand them.
+	Kind string `json:"kind,omitempty"`
 }
 
-func CollectEvictions(ctx context.Context, client kubernetes.Interface, namespace string, prePodLabels map[string]string, postPods []corev1.Pod) ([]EvictionRecord, error) {
+// Stranded reports whether the evicted pod had no ready replacement by the
+// time evictions were collected.
+func (r EvictionRecord) Stranded() bool {
+	return r.RescheduleSeconds < 0
+}
+
+// CollectEvictions matches eviction events of pods known before the run to
+// the replacement that became ready next. A StatefulSet pod is replaced by a
+// pod of the same name; other controllers by any pod of the same app label.
+func CollectEvictions(ctx context.Context, client kubernetes.Interface, namespace string, prePodLabels, prePodKinds map[string]string, postPods []corev1.Pod) ([]EvictionRecord, error) {
 	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
 		FieldSelector: "involvedObject.kind=Pod",
 	})
@@ -30,6 +42,12 @@ func CollectEvictions(ctx context.Context, client kubernetes.Interface, namespac
 	}
 
 	readyTimes := readyTimesByAppLabel(postPods)
+	readyByName := map[string]time.Time{}
+	for i := range postPods {
+		if readyAt := podReadyTime(&postPods[i]); !readyAt.IsZero() {
+			readyByName[postPods[i].Name] = readyAt
+		}
+	}
 
 	records := make([]EvictionRecord, 0)
 	for _, event := range events.Items {
@@ -49,8 +67,19 @@ func CollectEvictions(ctx context.Context, client kubernetes.Interface, namespac
 			Reason:    event.Reason,
 			Message:   event.Message,
 			EvictedAt: evictedAt,
+			Kind:      prePodKinds[podName],
+		}
+		candidates := readyTimes[appLabel]
+		switch rec.Kind {
+		case KindPod:
+			candidates = nil
+		case KindStatefulSet:
+			candidates = nil
+			if readyAt, ok := readyByName[podName]; ok {
+				candidates = []time.Time{readyAt}
+			}
 		}
-		if reschedAt, ok := findRescheduleTime(readyTimes[appLabel], evictedAt); ok {
+		if reschedAt, ok := findRescheduleTime(candidates, evictedAt); ok {
 			rec.RescheduledAt = reschedAt
 			rec.RescheduleSeconds = reschedAt.Sub(evictedAt).Seconds()
 		} else {
@@ -127,3 +156,31 @@ func eventTimestamp(event *corev1.Event) time.Time {
 	}
 	return time.Now()
 }
+
+// CountEvictionEvents counts pod eviction events in namespace whose timestamp
+// falls within [start, end]. Both kubelet "Evicted" events and descheduler
+// "Descheduled" actions are counted.
+func CountEvictionEvents(ctx context.Context, client kubernetes.Interface, namespace string, start, end time.Time) (int, error) {
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=Pod",
+	})
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, event := range events.Items {
+		if !isEvictionEvent(&event) {
+			continue
+		}
+		ts := eventTimestamp(&event)
+		if ts.Before(start) || ts.After(end) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+func isEvictionEvent(event *corev1.Event) bool {
+	return event.Reason == "Evicted" || event.Action == "Descheduled"
+}
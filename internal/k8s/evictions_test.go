@@ -1,11 +1,13 @@
 package k8s
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestPodReadyTime(t *testing.T) {
@@ -35,3 +37,83 @@ func TestEventTimestamp(t *testing.T) {
 		t.Fatalf("expected %v, got %v", now, got)
 	}
 }
+
+func TestCountEvictionEvents(t *testing.T) {
+	start := time.Now()
+	client := fake.NewSimpleClientset(
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "in-window", Namespace: "ns"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "a"},
+			Action:         "Descheduled",
+			EventTime:      metav1.MicroTime{Time: start.Add(time.Second)},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "too-late", Namespace: "ns"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "b"},
+			Reason:         "Evicted",
+			EventTime:      metav1.MicroTime{Time: start.Add(time.Hour)},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "other", Namespace: "ns"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "c"},
+			Reason:         "Scheduled",
+			EventTime:      metav1.MicroTime{Time: start.Add(time.Second)},
+		},
+	)
+	got, err := CountEvictionEvents(context.Background(), client, "ns", start, start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("CountEvictionEvents failed: %v", err)
+	}
+	if got != 1 {
+		t.Fatalf("expected 1 eviction, got %d", got)
+	}
+}
+
+func TestCollectEvictionsByKind(t *testing.T) {
+	evicted := time.Date(2026, 2, 9, 2, 0, 0, 0, time.UTC)
+	event := func(pod string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: pod + ".evicted", Namespace: "ns"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod},
+			Reason:         "Evicted",
+			EventTime:      metav1.MicroTime{Time: evicted},
+		}
+	}
+	readyPod := func(name, app string, after time.Duration) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app.kubernetes.io/name": app}},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(evicted.Add(after)),
+			}}},
+		}
+	}
+	client := fake.NewSimpleClientset(event("dep-a"), event("sts-0"), event("sts-1"), event("bare-0"))
+	labels := map[string]string{"dep-a": "dep", "sts-0": "sts", "sts-1": "sts", "bare-0": "bare"}
+	kinds := map[string]string{"dep-a": KindDeployment, "sts-0": KindStatefulSet, "sts-1": KindStatefulSet, "bare-0": KindPod}
+	post := []corev1.Pod{
+		readyPod("dep-b", "dep", 4*time.Second),
+		// sts-0 came back; sts-1 did not, although its sibling is ready.
+		readyPod("sts-0", "sts", 7*time.Second),
+		// Another bare pod of the same app must not count as a replacement.
+		readyPod("bare-1", "bare", 2*time.Second),
+	}
+	records, err := CollectEvictions(context.Background(), client, "ns", labels, kinds, post)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	got := map[string]EvictionRecord{}
+	for _, rec := range records {
+		got[rec.PodName] = rec
+	}
+	if got["dep-a"].RescheduleSeconds != 4 || got["dep-a"].Kind != KindDeployment {
+		t.Fatalf("unexpected deployment record %+v", got["dep-a"])
+	}
+	if got["sts-0"].RescheduleSeconds != 7 {
+		t.Fatalf("expected sts-0 replaced by the pod of the same name, got %+v", got["sts-0"])
+	}
+	if !got["sts-1"].Stranded() || !got["bare-0"].Stranded() {
+		t.Fatalf("expected sts-1 and bare-0 stranded, got %+v and %+v", got["sts-1"], got["bare-0"])
+	}
+}
@@ -0,0 +1,53 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Benchmark pods carry their workload kind under KindLabel, since the owner
+// reference alone does not tell a Deployment's ReplicaSet from a bare one.
+const (
+	KindLabel       = "deschedbench/kind"
+	KindDeployment  = "deployment"
+	KindStatefulSet = "statefulset"
+	KindReplicaSet  = "replicaset"
+	KindJob         = "job"
+	KindPod         = "pod"
+)
+
+// PodKind returns the workload kind of pod: KindLabel when set, otherwise
+// derived from the controller owner reference. Pods without an owner are
+// KindPod.
+func PodKind(pod *corev1.Pod) string {
+	if kind := pod.Labels[KindLabel]; kind != "" {
+		return kind
+	}
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		switch ref.Kind {
+		case "StatefulSet":
+			return KindStatefulSet
+		case "Job":
+			return KindJob
+		case "ReplicaSet":
+			return KindReplicaSet
+		}
+		return ref.Kind
+	}
+	return KindPod
+}
+
+// PodNameToKind maps each pod name to its workload kind.
+func PodNameToKind(pods []corev1.Pod) map[string]string {
+	out := make(map[string]string, len(pods))
+	for i := range pods {
+		out[pods[i].Name] = PodKind(&pods[i])
+	}
+	return out
+}
+
+func isBarePod(pod *corev1.Pod) bool {
+	return len(pod.OwnerReferences) == 0
+}
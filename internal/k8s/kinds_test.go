@@ -0,0 +1,32 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodKind(t *testing.T) {
+	controller := true
+	owned := func(kind string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			OwnerReferences: []metav1.OwnerReference{{Kind: kind, Name: "x", Controller: &controller}},
+		}}
+	}
+	labelled := owned("ReplicaSet")
+	labelled.Labels = map[string]string{KindLabel: KindDeployment}
+
+	for pod, want := range map[*corev1.Pod]string{
+		labelled:             KindDeployment,
+		owned("ReplicaSet"):  KindReplicaSet,
+		owned("StatefulSet"): KindStatefulSet,
+		owned("Job"):         KindJob,
+		owned("DaemonSet"):   "DaemonSet",
+		{}:                   KindPod,
+	} {
+		if got := PodKind(pod); got != want {
+			t.Fatalf("PodKind(%v) = %q, want %q", pod.OwnerReferences, got, want)
+		}
+	}
+}
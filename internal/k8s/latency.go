@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Triggers that caused a benchmark pod to be created.
+const (
+	TriggerInitial     = "initial"
+	TriggerDrain       = "drain"
+	TriggerDescheduler = "descheduler"
+)
+
+// PodLatency breaks the startup of one pod into scheduling, container start
+// and readiness. Condition timestamps have one-second resolution. A
+// duration is -1 when the pod has not reached the later stage.
+type PodLatency struct {
+	PodName   string `json:"pod_name"`
+	AppLabel  string `json:"app_label"`
+	NodeName  string `json:"node_name"`
+	Trigger   string `json:"trigger"`
+	Iteration int    `json:"iteration,omitempty"`
+
+	CreatedAt   time.Time `json:"created_at"`
+	ScheduledAt time.Time `json:"scheduled_at,omitempty"`
+	RunningAt   time.Time `json:"running_at,omitempty"`
+	ReadyAt     time.Time `json:"ready_at,omitempty"`
+
+	SchedulingSeconds float64 `json:"creation_to_scheduled_seconds"`
+	StartSeconds      float64 `json:"scheduled_to_running_seconds"`
+	ReadySeconds      float64 `json:"running_to_ready_seconds"`
+}
+
+// PodLatencies computes the startup latency of every pod created at or
+// after since, tagged with trigger and iteration. Pods are ordered by
+// creation time.
+func PodLatencies(pods []corev1.Pod, trigger string, iteration int, since time.Time) []PodLatency {
+	out := make([]PodLatency, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		created := pod.CreationTimestamp.Time
+		if created.Before(since) {
+			continue
+		}
+		rec := PodLatency{
+			PodName:     pod.Name,
+			AppLabel:    pod.Labels["app.kubernetes.io/name"],
+			NodeName:    pod.Spec.NodeName,
+			Trigger:     trigger,
+			Iteration:   iteration,
+			CreatedAt:   created,
+			ScheduledAt: conditionTime(pod, corev1.PodScheduled),
+			RunningAt:   podRunningTime(pod),
+			ReadyAt:     podReadyTime(pod),
+		}
+		rec.SchedulingSeconds = secondsBetween(rec.CreatedAt, rec.ScheduledAt)
+		rec.StartSeconds = secondsBetween(rec.ScheduledAt, rec.RunningAt)
+		rec.ReadySeconds = secondsBetween(rec.RunningAt, rec.ReadyAt)
+		out = append(out, rec)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// podRunningTime is when the last container of pod started running,
+// falling back to the Initialized condition when no container reports a
+// start time.
+func podRunningTime(pod *corev1.Pod) time.Time {
+	var latest time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if running := status.State.Running; running != nil && running.StartedAt.Time.After(latest) {
+			latest = running.StartedAt.Time
+		}
+	}
+	if latest.IsZero() {
+		return conditionTime(pod, corev1.PodInitialized)
+	}
+	return latest
+}
+
+func conditionTime(pod *corev1.Pod, condition corev1.PodConditionType) time.Time {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == condition && cond.Status == corev1.ConditionTrue {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
+// secondsBetween clamps to zero because the second-resolution condition
+// timestamps can order two stages of the same second either way.
+func secondsBetween(from, to time.Time) float64 {
+	if from.IsZero() || to.IsZero() {
+		return -1
+	}
+	if d := to.Sub(from).Seconds(); d > 0 {
+		return d
+	}
+	return 0
+}
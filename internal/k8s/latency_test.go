@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func latencyPod(name string, created time.Time, scheduled, running, ready time.Duration) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{"app.kubernetes.io/name": "web"},
+		},
+		Spec: corev1.PodSpec{NodeName: "w1"},
+	}
+	if scheduled > 0 {
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+			Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(scheduled)),
+		})
+	}
+	if running > 0 {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(created.Add(running))}},
+		}}
+	}
+	if ready > 0 {
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+			Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(ready)),
+		})
+	}
+	return pod
+}
+
+func TestPodLatencies(t *testing.T) {
+	t0 := time.Date(2026, 2, 9, 2, 50, 0, 0, time.UTC)
+	pods := []corev1.Pod{
+		latencyPod("late", t0.Add(10*time.Second), time.Second, 0, 0),
+		latencyPod("old", t0.Add(-time.Minute), time.Second, 2*time.Second, 3*time.Second),
+		latencyPod("new", t0, 2*time.Second, 5*time.Second, 6*time.Second),
+	}
+	got := PodLatencies(pods, TriggerDrain, 2, t0)
+	if len(got) != 2 || got[0].PodName != "new" || got[1].PodName != "late" {
+		t.Fatalf("expected pods created since t0 in creation order, got %+v", got)
+	}
+	p := got[0]
+	if p.Trigger != TriggerDrain || p.Iteration != 2 || p.AppLabel != "web" || p.NodeName != "w1" {
+		t.Fatalf("unexpected pod metadata: %+v", p)
+	}
+	if p.SchedulingSeconds != 2 || p.StartSeconds != 3 || p.ReadySeconds != 1 {
+		t.Fatalf("unexpected breakdown: %+v", p)
+	}
+	if got[1].SchedulingSeconds != 1 || got[1].StartSeconds != -1 || got[1].ReadySeconds != -1 {
+		t.Fatalf("expected unreached stages as -1, got %+v", got[1])
+	}
+}
+
+func TestPodRunningTimeFallsBackToInitialized(t *testing.T) {
+	t0 := time.Date(2026, 2, 9, 2, 50, 0, 0, time.UTC)
+	pod := corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+		{Type: corev1.PodInitialized, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(t0)},
+	}}}
+	if got := podRunningTime(&pod); !got.Equal(t0) {
+		t.Fatalf("expected Initialized time, got %v", got)
+	}
+}
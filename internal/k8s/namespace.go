@@ -12,7 +12,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-func EnsureNamespace(ctx context.Context, client kubernetes.Interface, name string) error {
+// EnsureNamespace creates the namespace if it does not exist, marking it as
+// managed by the given run.
+func EnsureNamespace(ctx context.Context, client kubernetes.Interface, name, runID string) error {
 	_, err := client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
 	if err == nil {
 		return nil
@@ -22,6 +24,7 @@ func EnsureNamespace(ctx context.Context, client kubernetes.Interface, name stri
 	}
 
 	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	MarkManaged(&ns.ObjectMeta, runID)
 	_, err = client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
 	if err != nil && !errors.IsAlreadyExists(err) {
 		return fmt.Errorf("failed to create namespace %s: %v", name, err)
@@ -34,3 +34,13 @@ func UnschedulableNodeNames(nodes []corev1.Node) []string {
 	sort.Strings(names)
 	return names
 }
+
+// IsControlPlaneNode reports whether labels carry a control-plane (or legacy
+// master) role. The benchmark never schedules onto or drains those nodes.
+func IsControlPlaneNode(labels map[string]string) bool {
+	if _, ok := labels["node-role.kubernetes.io/control-plane"]; ok {
+		return true
+	}
+	_, ok := labels["node-role.kubernetes.io/master"]
+	return ok
+}
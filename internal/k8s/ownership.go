@@ -0,0 +1,33 @@
+package k8s
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Every object deschedbench creates carries ManagedLabel=true and the run ID
+// annotation so cleanup can find it even after the run namespace is gone.
+const (
+	ManagedLabel    = "deschedbench"
+	ManagedSelector = ManagedLabel + "=true"
+	RunIDAnnotation = "deschedbench/run-id"
+)
+
+// MarkManaged labels obj as created by deschedbench and records the run ID.
+func MarkManaged(obj *metav1.ObjectMeta, runID string) {
+	if obj.Labels == nil {
+		obj.Labels = map[string]string{}
+	}
+	obj.Labels[ManagedLabel] = "true"
+	if runID == "" {
+		return
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Annotations[RunIDAnnotation] = runID
+}
+
+// RunIDOf returns the run ID recorded on obj, if any.
+func RunIDOf(obj metav1.Object) string {
+	return obj.GetAnnotations()[RunIDAnnotation]
+}
@@ -3,6 +3,9 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -77,6 +80,71 @@ func SummarizeScheduling(ctx context.Context, client kubernetes.Interface, names
 	return summary, nil
 }
 
+// PendingReason counts pods the scheduler could not place for the same
+// reason.
+type PendingReason struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Pods    int    `json:"pods"`
+}
+
+// PendingReasons groups unscheduled pods by PodScheduled reason and
+// normalized message, largest group first.
+func PendingReasons(pods []corev1.Pod) []PendingReason {
+	counts := map[PendingReason]int{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != "" {
+			continue
+		}
+		reason, message := podSchedulingFailure(pod)
+		if reason == "" && message == "" {
+			continue
+		}
+		counts[PendingReason{Reason: reason, Message: NormalizeSchedulingMessage(message)}]++
+	}
+	out := make([]PendingReason, 0, len(counts))
+	for key, n := range counts {
+		key.Pods = n
+		out = append(out, key)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Pods != out[j].Pods {
+			return out[i].Pods > out[j].Pods
+		}
+		if out[i].Reason != out[j].Reason {
+			return out[i].Reason < out[j].Reason
+		}
+		return out[i].Message < out[j].Message
+	})
+	return out
+}
+
+var leadingCount = regexp.MustCompile(`^\d+ `)
+
+// NormalizeSchedulingMessage drops node counts and the preemption part from
+// a scheduler message, so pods blocked for the same reasons group together
+// while nodes drain and refill:
+//
+//	0/4 nodes are available: 1 node(s) were unschedulable, 3 Insufficient cpu. preemption: ...
+//
+// becomes "Insufficient cpu, node(s) were unschedulable". Other messages are
+// returned unchanged.
+func NormalizeSchedulingMessage(message string) string {
+	const available = "nodes are available: "
+	msg, _, _ := strings.Cut(message, ". preemption:")
+	_, msg, ok := strings.Cut(msg, available)
+	if !ok {
+		return message
+	}
+	parts := strings.Split(strings.TrimSuffix(msg, "."), ", ")
+	for i, part := range parts {
+		parts[i] = leadingCount.ReplaceAllString(strings.TrimSpace(part), "")
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
 func podSchedulingFailure(pod *corev1.Pod) (string, string) {
 	for _, cond := range pod.Status.Conditions {
 		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
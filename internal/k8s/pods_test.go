@@ -54,3 +54,47 @@ func TestSummarizeScheduling(t *testing.T) {
 		t.Fatalf("expected 1 message, got %d", len(summary.Messages))
 	}
 }
+
+func TestNormalizeSchedulingMessage(t *testing.T) {
+	msg := "0/4 nodes are available: 1 node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }, 1 node(s) were unschedulable, 2 Insufficient cpu. preemption: 0/4 nodes are available: 1 Preemption is not helpful for scheduling, 3 No preemption victims found for incoming pod."
+	want := "Insufficient cpu, node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }, node(s) were unschedulable"
+	if got := NormalizeSchedulingMessage(msg); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := NormalizeSchedulingMessage("pod has unbound immediate PersistentVolumeClaims"); got != "pod has unbound immediate PersistentVolumeClaims" {
+		t.Fatalf("expected other messages unchanged, got %q", got)
+	}
+}
+
+func TestPendingReasons(t *testing.T) {
+	pending := func(name, message string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  "Unschedulable",
+					Message: message,
+				}},
+			},
+		}
+	}
+	pods := []corev1.Pod{
+		pending("a", "0/3 nodes are available: 3 Insufficient cpu."),
+		pending("b", "0/4 nodes are available: 4 Insufficient cpu."),
+		pending("c", "0/4 nodes are available: 1 node(s) were unschedulable, 3 Insufficient cpu."),
+		{ObjectMeta: metav1.ObjectMeta{Name: "scheduled"}, Spec: corev1.PodSpec{NodeName: "w1"}},
+	}
+	got := PendingReasons(pods)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", got)
+	}
+	if got[0].Message != "Insufficient cpu" || got[0].Pods != 2 || got[0].Reason != "Unschedulable" {
+		t.Fatalf("expected the largest group first, got %+v", got[0])
+	}
+	if got[1].Message != "Insufficient cpu, node(s) were unschedulable" || got[1].Pods != 1 {
+		t.Fatalf("unexpected second group %+v", got[1])
+	}
+}
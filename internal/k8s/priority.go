@@ -0,0 +1,164 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PriorityLabel carries the priority tier of a benchmark pod, so reports
+// group by tier instead of by the per-run PriorityClass name.
+const PriorityLabel = "deschedbench/priority"
+
+// Disruption causes.
+const (
+	CauseEvicted   = "evicted"
+	CausePreempted = "preempted"
+)
+
+// PriorityClassName names the PriorityClass of tier for one run. Priority
+// classes are cluster-scoped, so the run ID keeps concurrent runs apart.
+func PriorityClassName(runID, tier string) string {
+	return fmt.Sprintf("deschedbench-%s-%s", runID, tier)
+}
+
+// BuildPriorityClass renders a managed PriorityClass that may preempt lower
+// priorities.
+func BuildPriorityClass(name string, value int32, runID string) *schedulingv1.PriorityClass {
+	preempt := corev1.PreemptLowerPriority
+	pc := &schedulingv1.PriorityClass{
+		ObjectMeta:       metav1.ObjectMeta{Name: name},
+		Value:            value,
+		PreemptionPolicy: &preempt,
+		Description:      "deschedbench priority tier",
+	}
+	MarkManaged(&pc.ObjectMeta, runID)
+	return pc
+}
+
+// EnsurePriorityClass creates pc. The value of an existing class cannot
+// change, so one that already exists is kept as it is.
+func EnsurePriorityClass(ctx context.Context, client kubernetes.Interface, pc *schedulingv1.PriorityClass) error {
+	_, err := client.SchedulingV1().PriorityClasses().Create(ctx, pc, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// PodPriority returns the priority tier of pod: PriorityLabel when set,
+// otherwise its PriorityClass name. It is empty for pods without either.
+func PodPriority(pod *corev1.Pod) string {
+	if tier := pod.Labels[PriorityLabel]; tier != "" {
+		return tier
+	}
+	return pod.Spec.PriorityClassName
+}
+
+// PodNameToPriority maps each pod name to its priority tier.
+func PodNameToPriority(pods []corev1.Pod) map[string]string {
+	out := make(map[string]string, len(pods))
+	for i := range pods {
+		out[pods[i].Name] = PodPriority(&pods[i])
+	}
+	return out
+}
+
+// Disruption is one benchmark pod evicted or preempted while a drain or a
+// descheduler pass was in progress.
+type Disruption struct {
+	PodName   string    `json:"pod_name"`
+	NodeName  string    `json:"node_name,omitempty"`
+	Priority  string    `json:"priority"`
+	Cause     string    `json:"cause"`
+	Trigger   string    `json:"trigger"`
+	Iteration int       `json:"iteration"`
+	Time      time.Time `json:"time"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// DrainDisruptions records the pods a drain of node evicts. Bare pods are
+// left out, as DrainNode skips them.
+func DrainDisruptions(pods []corev1.Pod, node string, iteration int, at time.Time) []Disruption {
+	var out []Disruption
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName != node || isBarePod(pod) {
+			continue
+		}
+		out = append(out, Disruption{
+			PodName:   pod.Name,
+			NodeName:  node,
+			Priority:  PodPriority(pod),
+			Cause:     CauseEvicted,
+			Trigger:   TriggerDrain,
+			Iteration: iteration,
+			Time:      at,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].PodName < out[j].PodName })
+	return out
+}
+
+// CollectDisruptions returns the eviction and preemption events of pods in
+// namespace within [start, end]. Pods missing from priorities have an empty
+// priority. Trigger and Iteration are left for the caller, which knows what
+// was running in the window.
+func CollectDisruptions(ctx context.Context, client kubernetes.Interface, namespace string, priorities map[string]string, start, end time.Time) ([]Disruption, error) {
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.kind=Pod",
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out []Disruption
+	for i := range events.Items {
+		event := &events.Items[i]
+		cause := ""
+		switch {
+		case isPreemptionEvent(event):
+			cause = CausePreempted
+		case isEvictionEvent(event):
+			cause = CauseEvicted
+		default:
+			continue
+		}
+		ts := eventTimestamp(event)
+		if ts.Before(start) || ts.After(end) {
+			continue
+		}
+		out = append(out, Disruption{
+			PodName:  event.InvolvedObject.Name,
+			NodeName: eventNode(event),
+			Priority: priorities[event.InvolvedObject.Name],
+			Cause:    cause,
+			Time:     ts,
+			Message:  event.Message,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out, nil
+}
+
+// isPreemptionEvent matches the event the scheduler records on a victim:
+// reason Preempted, "Preempted by pod <uid> on node <node>".
+func isPreemptionEvent(event *corev1.Event) bool {
+	return event.Reason == "Preempted"
+}
+
+// eventNode returns the node of a preemption message, falling back to the
+// event source.
+func eventNode(event *corev1.Event) string {
+	if _, node, ok := strings.Cut(event.Message, " on node "); ok {
+		return strings.TrimSpace(node)
+	}
+	return event.Source.Host
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDrainDisruptions(t *testing.T) {
+	controller := true
+	pod := func(name, node, tier string, owned bool) corev1.Pod {
+		p := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{PriorityLabel: tier}},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+		if owned {
+			p.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs", Controller: &controller}}
+		}
+		return p
+	}
+	at := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+	got := DrainDisruptions([]corev1.Pod{
+		pod("b", "w1", "high", true),
+		pod("a", "w1", "", true),
+		pod("c", "w2", "low", true),
+		pod("d", "w1", "low", false),
+	}, "w1", 2, at)
+	if len(got) != 2 || got[0].PodName != "a" || got[1].PodName != "b" {
+		t.Fatalf("unexpected disruptions: %+v", got)
+	}
+	if got[1].Priority != "high" || got[1].Cause != CauseEvicted || got[1].Trigger != TriggerDrain || got[1].Iteration != 2 || !got[1].Time.Equal(at) {
+		t.Fatalf("unexpected disruption: %+v", got[1])
+	}
+}
+
+func TestCollectDisruptions(t *testing.T) {
+	start := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+	event := func(name, pod, reason, message string, at time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "bench"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod},
+			Reason:         reason,
+			Message:        message,
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+	client := fake.NewSimpleClientset(
+		event("e1", "p1", "Preempted", "Preempted by pod 1234 on node w2", start.Add(time.Second)),
+		event("e2", "p2", "Scheduled", "assigned", start.Add(time.Second)),
+		event("e3", "p3", "Preempted", "Preempted by pod 1234 on node w2", start.Add(-time.Minute)),
+	)
+	got, err := CollectDisruptions(context.Background(), client, "bench", map[string]string{"p1": "low"}, start, start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("CollectDisruptions failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected one disruption, got %+v", got)
+	}
+	if d := got[0]; d.PodName != "p1" || d.NodeName != "w2" || d.Priority != "low" || d.Cause != CausePreempted {
+		t.Fatalf("unexpected disruption: %+v", d)
+	}
+}
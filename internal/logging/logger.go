@@ -1,31 +1,125 @@
 package logging
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 	"sync"
 )
 
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// TimeFormat keeps millisecond precision so phase boundaries can be
+	// lined up with sampler output and cluster events.
+	TimeFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// Options configures the process-wide logger.
+type Options struct {
+	Format string
+	Level  string
+	// File, when set, receives a copy of every record written to stdout.
+	File string
+}
+
 var (
-	logger *slog.Logger
-	once   sync.Once
+	logger  *slog.Logger
+	once    sync.Once
+	mu      sync.Mutex
+	logFile *os.File
 )
 
 func InitLogger() {
 	once.Do(func() {
-		handler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelInfo,
-			ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
-				if attr.Key == slog.TimeKey {
-					attr.Value = slog.StringValue(attr.Value.Time().Format("2006-01-02T15:04:05"))
-				}
-				return attr
-			},
-		})
-		logger = slog.New(handler)
+		logger = slog.New(newHandler(os.Stdout, FormatText, slog.LevelInfo))
 	})
 }
 
+// Configure replaces the process-wide logger. It is meant to be called once
+// at startup, before any component captures GetLogger.
+func Configure(opts Options) error {
+	format := strings.ToLower(strings.TrimSpace(opts.Format))
+	if format == "" {
+		format = FormatText
+	}
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported log format %q (want text or json)", opts.Format)
+	}
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if opts.File != "" {
+		file, err = os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("open log file: %w", err)
+		}
+		out = io.MultiWriter(os.Stdout, file)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	once.Do(func() {})
+	if logFile != nil {
+		_ = logFile.Close()
+	}
+	logFile = file
+	logger = slog.New(newHandler(out, format, level))
+	return nil
+}
+
+// Close flushes and closes the log file opened by Configure, if any.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if logFile == nil {
+		return nil
+	}
+	err := logFile.Close()
+	logFile = nil
+	return err
+}
+
+// ParseLevel maps debug, info, warn and error to slog levels. An empty
+// string means info.
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unsupported log level %q (want debug, info, warn or error)", value)
+	}
+}
+
+func newHandler(w io.Writer, format string, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				attr.Value = slog.StringValue(attr.Value.Time().Format(TimeFormat))
+			}
+			return attr
+		},
+	}
+	if format == FormatJSON {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
 func GetLogger() *slog.Logger {
 	if logger == nil {
 		InitLogger()
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigureJSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.log")
+	if err := Configure(Options{Format: "json", Level: "warn", File: path}); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = Close()
+		_ = Configure(Options{})
+	})
+
+	GetLogger().Info("dropped")
+	GetLogger().With(StringField("run_id", "run-a")).Warn("kept", StringField("node", "w1"))
+	if err := Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one record above warn, got %d: %q", len(lines), data)
+	}
+	var record map[string]string
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("decode record: %v", err)
+	}
+	if record["msg"] != "kept" || record["run_id"] != "run-a" || record["node"] != "w1" {
+		t.Fatalf("unexpected record: %v", record)
+	}
+	ts, err := time.Parse(TimeFormat, record["time"])
+	if err != nil {
+		t.Fatalf("parse time %q: %v", record["time"], err)
+	}
+	if !strings.Contains(record["time"], ".") || ts.IsZero() {
+		t.Fatalf("expected millisecond timestamp, got %q", record["time"])
+	}
+}
+
+func TestConfigureRejectsUnknownValues(t *testing.T) {
+	if err := Configure(Options{Format: "xml"}); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+	if err := Configure(Options{Level: "trace"}); err == nil {
+		t.Fatalf("expected error for unknown level")
+	}
+}
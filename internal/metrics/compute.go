@@ -3,6 +3,8 @@ package metrics
 import (
 	"math"
 	"time"
+
+	"k8s-descheduler-benchmark/internal/k8s"
 )
 
 type Sample struct {
@@ -12,6 +14,9 @@ type Sample struct {
 	UnschedulablePods int       `json:"unschedulable_pods"`
 	NodesCount        int       `json:"nodes_count"`
 	PodsCounted       int       `json:"pods_counted"`
+	// PendingReasons groups unscheduled benchmark pods by PodScheduled
+	// reason and message.
+	PendingReasons []k8s.PendingReason `json:"pending_reasons,omitempty"`
 }
 
 func DeriveSample(snapshot Snapshot) Sample {
@@ -31,7 +36,23 @@ func DeriveSample(snapshot Snapshot) Sample {
 		UnschedulablePods: snapshot.UnschedulablePods,
 		NodesCount:        len(snapshot.Nodes),
 		PodsCounted:       snapshot.TotalPodsCounted,
+		PendingReasons:    snapshot.PendingReasons,
+	}
+}
+
+// CPURequestedStddev is the standard deviation across nodes of requested
+// CPU as a percentage of allocatable, the utilization LowNodeUtilization
+// compares against its thresholds. Nodes without allocatable CPU are left
+// out.
+func CPURequestedStddev(snapshot Snapshot) float64 {
+	percents := make([]float64, 0, len(snapshot.Nodes))
+	for _, node := range snapshot.Nodes {
+		if node.CPUAllocatableMilli <= 0 {
+			continue
+		}
+		percents = append(percents, 100*float64(node.CPURequestedMilli)/float64(node.CPUAllocatableMilli))
 	}
+	return stddev(percents)
 }
 
 func stddev(values []float64) float64 {
@@ -44,3 +44,15 @@ func TestDeriveSampleBasic(t *testing.T) {
 		t.Fatalf("unexpected counts: nodes=%d pods=%d", sample.NodesCount, sample.PodsCounted)
 	}
 }
+
+func TestCPURequestedStddev(t *testing.T) {
+	snap := Snapshot{Nodes: map[string]NodeStats{
+		"n1":     {CPUAllocatableMilli: 1000, CPURequestedMilli: 200},
+		"n2":     {CPUAllocatableMilli: 2000, CPURequestedMilli: 1200},
+		"broken": {},
+	}}
+	// 20% and 60%: mean 40, population stddev 20.
+	if got := CPURequestedStddev(snap); math.Abs(got-20) > 1e-9 {
+		t.Fatalf("expected stddev 20, got %f", got)
+	}
+}
@@ -3,17 +3,24 @@ package metrics
 import (
 	"fmt"
 	"net/http"
+	"sync"
 
 	"k8s-descheduler-benchmark/internal/logging"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+var serverOnce sync.Once
+
+// StartMetricsServer serves the registry on port. Repeated calls within one
+// process (e.g. several runs of an image comparison) reuse the first server.
 func StartMetricsServer(port int) {
-	http.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
-	go func() {
-		if err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil); err != nil {
-			logging.GetLogger().Error("metrics server error", logging.ErrorField(err))
-		}
-	}()
+	serverOnce.Do(func() {
+		http.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+		go func() {
+			if err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil); err != nil {
+				logging.GetLogger().Error("metrics server error", logging.ErrorField(err))
+			}
+		}()
+	})
 }
@@ -1,9 +1,38 @@
 package metrics
 
+import "sync"
+
+var (
+	pendingMu   sync.Mutex
+	pendingSeen = map[[2]string]struct{}{}
+)
+
 func RecordSample(sample Sample) {
 	PodsStddev.Set(sample.PodsStddev)
 	PodsMaxMinRatio.Set(sample.PodsMaxMinRatio)
 	UnschedulablePods.Set(float64(sample.UnschedulablePods))
+	recordPending(sample)
+}
+
+// recordPending sets the pending gauge for every reason in sample and
+// drops reasons seen earlier to zero, so a cleared reason reads 0 rather
+// than its last value.
+func recordPending(sample Sample) {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	current := map[[2]string]int{}
+	for _, r := range sample.PendingReasons {
+		current[[2]string{r.Reason, r.Message}] += r.Pods
+	}
+	for key := range pendingSeen {
+		if _, ok := current[key]; !ok {
+			PendingPods.WithLabelValues(key[0], key[1]).Set(0)
+		}
+	}
+	for key, n := range current {
+		pendingSeen[key] = struct{}{}
+		PendingPods.WithLabelValues(key[0], key[1]).Set(float64(n))
+	}
 }
 
 func RecordPhase(name string) {
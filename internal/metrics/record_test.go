@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"testing"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecordSamplePendingReasons(t *testing.T) {
+	RecordSample(Sample{PendingReasons: []k8s.PendingReason{
+		{Reason: "Unschedulable", Message: "Insufficient cpu", Pods: 3},
+		{Reason: "Unschedulable", Message: "node(s) were unschedulable", Pods: 1},
+	}})
+	if got := testutil.ToFloat64(PendingPods.WithLabelValues("Unschedulable", "Insufficient cpu")); got != 3 {
+		t.Fatalf("expected 3 pending for Insufficient cpu, got %v", got)
+	}
+
+	RecordSample(Sample{PendingReasons: []k8s.PendingReason{
+		{Reason: "Unschedulable", Message: "node(s) were unschedulable", Pods: 2},
+	}})
+	if got := testutil.ToFloat64(PendingPods.WithLabelValues("Unschedulable", "Insufficient cpu")); got != 0 {
+		t.Fatalf("expected a cleared reason to read 0, got %v", got)
+	}
+	if got := testutil.ToFloat64(PendingPods.WithLabelValues("Unschedulable", "node(s) were unschedulable")); got != 2 {
+		t.Fatalf("expected 2 pending for unschedulable nodes, got %v", got)
+	}
+}
@@ -40,6 +40,11 @@ var (
 		Name: "deschedbench_unschedulable_pods",
 		Help: "Count of unschedulable pods in the benchmark namespace",
 	})
+
+	PendingPods = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "deschedbench_pending_pods",
+		Help: "Unscheduled pods in the benchmark namespace by PodScheduled reason and normalized message",
+	}, []string{"reason", "message"})
 )
 
 var Registry = prometheus.NewRegistry()
@@ -52,4 +57,5 @@ func init() {
 	Registry.MustRegister(PodsStddev)
 	Registry.MustRegister(PodsMaxMinRatio)
 	Registry.MustRegister(UnschedulablePods)
+	Registry.MustRegister(PendingPods)
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"k8s-descheduler-benchmark/internal/k8s"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -16,6 +18,9 @@ type Snapshot struct {
 	TotalPodsCounted  int                  `json:"total_pods_counted"`
 	Namespace         string               `json:"namespace"`
 	NamespaceOnly     bool                 `json:"namespace_only"`
+	// PendingReasons groups the unscheduled pods of Namespace by
+	// PodScheduled reason and message.
+	PendingReasons []k8s.PendingReason `json:"pending_reasons,omitempty"`
 }
 
 type NodeStats struct {
@@ -56,6 +61,7 @@ func CollectSnapshot(ctx context.Context, client kubernetes.Interface, opts Snap
 	if err != nil {
 		return snap, err
 	}
+	var namespacePods []corev1.Pod
 	for _, pod := range pods.Items {
 		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
 			continue
@@ -77,8 +83,10 @@ func CollectSnapshot(ctx context.Context, client kubernetes.Interface, opts Snap
 			if isUnschedulable(&pod) {
 				snap.UnschedulablePods++
 			}
+			namespacePods = append(namespacePods, pod)
 		}
 	}
+	snap.PendingReasons = k8s.PendingReasons(namespacePods)
 
 	return snap, nil
 }
@@ -0,0 +1,189 @@
+package promquery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"sigs.k8s.io/yaml"
+)
+
+// Query is a named PromQL expression evaluated over the run window.
+type Query struct {
+	Name string `json:"name"`
+	Expr string `json:"expr"`
+}
+
+// Point is one sample of a range query.
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+type Series struct {
+	Labels  map[string]string `json:"labels,omitempty"`
+	Summary *Summary          `json:"summary,omitempty"`
+	Points  []Point           `json:"points"`
+}
+
+// Summary aggregates the points of one series.
+type Summary struct {
+	Avg float64 `json:"avg"`
+	Max float64 `json:"max"`
+	P99 float64 `json:"p99"`
+}
+
+// Result is one evaluated query. Summary is set only when the query returns
+// a single series; grouped queries carry a summary per series instead.
+type Result struct {
+	Name    string   `json:"name"`
+	Expr    string   `json:"expr"`
+	Summary *Summary `json:"summary,omitempty"`
+	Series  []Series `json:"series"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Window is the time range and resolution queries are evaluated over.
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Step  string    `json:"step"`
+}
+
+// Enrichment is what a run embeds in its result file.
+type Enrichment struct {
+	URL     string   `json:"url"`
+	Window  Window   `json:"window"`
+	Queries []Result `json:"queries"`
+}
+
+// DefaultQueries mirror the apiserver-pressure and descheduler-impact
+// dashboards, plus scheduler attempts.
+func DefaultQueries() []Query {
+	return []Query{
+		{Name: "apiserver_pods_list_qps", Expr: `sum(rate(apiserver_request_total{resource="pods", verb="LIST"}[30s]))`},
+		{Name: "apiserver_pods_get_qps", Expr: `sum(rate(apiserver_request_total{resource="pods", verb="GET"}[30s]))`},
+		{Name: "apiserver_pods_list_p99_seconds", Expr: `histogram_quantile(0.99, sum by (le) (rate(apiserver_request_duration_seconds_bucket{resource="pods", verb="LIST"}[30s])))`},
+		{Name: "apiserver_pods_get_p99_seconds", Expr: `histogram_quantile(0.99, sum by (le) (rate(apiserver_request_duration_seconds_bucket{resource="pods", verb="GET"}[30s])))`},
+		{Name: "apiserver_5xx_rate", Expr: `sum(rate(apiserver_request_total{code=~"5.."}[30s]))`},
+		{Name: "scheduler_attempts_rate", Expr: `sum by (result) (rate(scheduler_schedule_attempts_total[1m]))`},
+		{Name: "descheduler_evictions", Expr: `sum by (strategy) (increase(descheduler_pods_evicted[1m]))`},
+	}
+}
+
+// LoadQueries reads a YAML or JSON list of name/expr pairs.
+func LoadQueries(path string) ([]Query, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var queries []Query
+	if err := yaml.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("parse queries %s: %w", path, err)
+	}
+	for i, q := range queries {
+		if q.Name == "" || q.Expr == "" {
+			return nil, fmt.Errorf("query %d in %s needs name and expr", i, path)
+		}
+	}
+	return queries, nil
+}
+
+type Client struct {
+	url string
+	api v1.API
+}
+
+func NewClient(url string) (*Client, error) {
+	c, err := api.NewClient(api.Config{Address: url})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{url: url, api: v1.NewAPI(c)}, nil
+}
+
+// Enrich evaluates queries over window. A failing query records its error
+// and does not stop the others.
+func (c *Client) Enrich(ctx context.Context, queries []Query, window Window) Enrichment {
+	step, err := time.ParseDuration(window.Step)
+	if err != nil || step <= 0 {
+		step = 15 * time.Second
+		window.Step = step.String()
+	}
+	out := Enrichment{URL: c.url, Window: window}
+	for _, q := range queries {
+		result := Result{Name: q.Name, Expr: q.Expr, Series: []Series{}}
+		value, _, err := c.api.QueryRange(ctx, q.Expr, v1.Range{Start: window.Start, End: window.End, Step: step})
+		if err != nil {
+			result.Error = err.Error()
+			out.Queries = append(out.Queries, result)
+			continue
+		}
+		matrix, ok := value.(model.Matrix)
+		if !ok {
+			result.Error = fmt.Sprintf("unexpected result type %s", value.Type())
+			out.Queries = append(out.Queries, result)
+			continue
+		}
+		result.Series = convert(matrix)
+		if len(result.Series) == 1 {
+			result.Summary = result.Series[0].Summary
+		}
+		out.Queries = append(out.Queries, result)
+	}
+	return out
+}
+
+func convert(matrix model.Matrix) []Series {
+	out := make([]Series, 0, len(matrix))
+	for _, stream := range matrix {
+		series := Series{Points: make([]Point, 0, len(stream.Values))}
+		if len(stream.Metric) > 0 {
+			series.Labels = map[string]string{}
+			for k, v := range stream.Metric {
+				series.Labels[string(k)] = string(v)
+			}
+		}
+		for _, pair := range stream.Values {
+			// histogram_quantile yields NaN for idle windows; JSON cannot
+			// carry it, so such points are dropped.
+			if math.IsNaN(float64(pair.Value)) || math.IsInf(float64(pair.Value), 0) {
+				continue
+			}
+			series.Points = append(series.Points, Point{Time: pair.Timestamp.Time().UTC(), Value: float64(pair.Value)})
+		}
+		series.Summary = Summarize(series.Points)
+		out = append(out, series)
+	}
+	return out
+}
+
+// Summarize returns avg, max and nearest-rank p99 over the points of one
+// series, or nil when there is none. Series are never pooled: the points of
+// differently labelled series measure different things.
+func Summarize(points []Point) *Summary {
+	values := make([]float64, 0, len(points))
+	for _, p := range points {
+		values = append(values, p.Value)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	rank := int(math.Ceil(0.99*float64(len(values)))) - 1
+	return &Summary{
+		Avg: sum / float64(len(values)),
+		Max: values[len(values)-1],
+		P99: values[rank],
+	}
+}
@@ -0,0 +1,96 @@
+package promquery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnrich(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Form.Get("query") {
+		case "up":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"job":"apiserver"},"values":[[1700000000,"1"],[1700000015,"3"],[1700000030,"NaN"]]},
+				{"metric":{"job":"scheduler"},"values":[[1700000000,"2"]]}
+			]}}`))
+		case "sum(up)":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{},"values":[[1700000000,"3"],[1700000015,"5"]]}
+			]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	start := time.Unix(1700000000, 0)
+	out := client.Enrich(context.Background(), []Query{
+		{Name: "up", Expr: "up"},
+		{Name: "total", Expr: "sum(up)"},
+		{Name: "broken", Expr: "sum("},
+	}, Window{Start: start, End: start.Add(30 * time.Second), Step: "15s"})
+
+	if len(out.Queries) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(out.Queries))
+	}
+	up := out.Queries[0]
+	if len(up.Series) != 2 || len(up.Series[0].Points) != 2 || up.Series[0].Labels["job"] != "apiserver" {
+		t.Fatalf("unexpected series: %+v", up.Series)
+	}
+	if up.Summary != nil {
+		t.Fatalf("expected no pooled summary over two series, got %+v", up.Summary)
+	}
+	apiserver, scheduler := up.Series[0].Summary, up.Series[1].Summary
+	if apiserver == nil || apiserver.Avg != 2 || apiserver.Max != 3 || apiserver.P99 != 3 {
+		t.Fatalf("unexpected apiserver summary: %+v", apiserver)
+	}
+	if scheduler == nil || scheduler.Avg != 2 || scheduler.Max != 2 || scheduler.P99 != 2 {
+		t.Fatalf("unexpected scheduler summary: %+v", scheduler)
+	}
+	total := out.Queries[1]
+	if total.Summary == nil || total.Summary != total.Series[0].Summary || total.Summary.Max != 5 {
+		t.Fatalf("expected a single series to carry the query summary: %+v", total)
+	}
+	if out.Queries[2].Error == "" || out.Queries[2].Summary != nil {
+		t.Fatalf("expected failed query to carry its error: %+v", out.Queries[2])
+	}
+}
+
+func TestSummarizeP99(t *testing.T) {
+	var points []Point
+	for i := 1; i <= 200; i++ {
+		points = append(points, Point{Value: float64(i)})
+	}
+	summary := Summarize(points)
+	if summary.P99 != 198 || summary.Max != 200 || summary.Avg != 100.5 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if Summarize(nil) != nil {
+		t.Fatalf("expected nil summary without points")
+	}
+}
+
+func TestLoadQueries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queries.yaml")
+	if err := os.WriteFile(path, []byte("- name: list_qps\n  expr: sum(rate(apiserver_request_total[30s]))\n- name: missing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadQueries(path); err == nil {
+		t.Fatalf("expected error for query without expr")
+	}
+}
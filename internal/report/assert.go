@@ -0,0 +1,181 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Assertion is a threshold on one summary field, such as
+// "after.pods_stddev<=1.0".
+type Assertion struct {
+	Expr  string  `json:"expr"`
+	Field string  `json:"field"`
+	Op    string  `json:"op"`
+	Value float64 `json:"value"`
+}
+
+// AssertionResult is the outcome of one assertion against a run.
+type AssertionResult struct {
+	Assertion
+	Actual float64 `json:"actual"`
+	Passed bool    `json:"passed"`
+	// Error is set when the assertion could not be evaluated, for example
+	// because the run failed before producing a summary.
+	Error string `json:"error,omitempty"`
+}
+
+// Message describes a failed or errored assertion.
+func (a AssertionResult) Message() string {
+	if a.Error != "" {
+		return a.Error
+	}
+	return fmt.Sprintf("%s = %s, want %s %s", a.Field, formatValue(a.Actual), a.Op, formatValue(a.Value))
+}
+
+// Operators are matched longest first so "<=" is not read as "<".
+var assertionOps = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// ParseAssertion parses "field<op>value". Field must be one of
+// AssertionFields.
+func ParseAssertion(expr string) (Assertion, error) {
+	for _, op := range assertionOps {
+		idx := strings.Index(expr, op)
+		if idx < 0 {
+			continue
+		}
+		field := strings.TrimSpace(expr[:idx])
+		raw := strings.TrimSpace(expr[idx+len(op):])
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return Assertion{}, fmt.Errorf("assertion %q: invalid value %q", expr, raw)
+		}
+		if !slices.Contains(AssertionFields(), field) {
+			return Assertion{}, fmt.Errorf("assertion %q: unknown field %q (known: %s)", expr, field, strings.Join(AssertionFields(), ", "))
+		}
+		return Assertion{Expr: expr, Field: field, Op: op, Value: value}, nil
+	}
+	return Assertion{}, fmt.Errorf("assertion %q: expected field<op>value with op one of %s", expr, strings.Join(assertionOps, " "))
+}
+
+// ParseAssertions parses every expression, stopping at the first error.
+func ParseAssertions(exprs []string) ([]Assertion, error) {
+	out := make([]Assertion, 0, len(exprs))
+	for _, expr := range exprs {
+		a, err := ParseAssertion(expr)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// AssertionFields lists the field names assertions accept.
+func AssertionFields() []string {
+	values := SummaryValues(Summary{ClusterWide: &ClusterBalance{}, Priority: &PrioritySummary{}}, 0)
+	fields := make([]string, 0, len(values))
+	for name := range values {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// SummaryValues flattens the numeric fields of s under their JSON names,
+// with before and after samples as "before.<field>" and "after.<field>".
+// The after fields are also available without a prefix, and evictions is
+// the number of evicted pods.
+func SummaryValues(s Summary, evictions int) map[string]float64 {
+	values := map[string]float64{"evictions": float64(evictions)}
+	data, _ := json.Marshal(s)
+	var doc map[string]any
+	_ = json.Unmarshal(data, &doc)
+	flattenNumbers(values, "", doc)
+	for name, v := range values {
+		if field, ok := strings.CutPrefix(name, "after."); ok {
+			values[field] = v
+		}
+	}
+	return values
+}
+
+func flattenNumbers(out map[string]float64, prefix string, doc map[string]any) {
+	for key, value := range doc {
+		switch v := value.(type) {
+		case float64:
+			out[prefix+key] = v
+		case map[string]any:
+			flattenNumbers(out, prefix+key+".", v)
+		}
+	}
+}
+
+// Evaluate checks each assertion against values. A negative
+// rebalance_time_seconds means the run never rebalanced, so any assertion on
+// it fails instead of being compared against -1.
+func Evaluate(assertions []Assertion, values map[string]float64) []AssertionResult {
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		actual, ok := values[a.Field]
+		res := AssertionResult{Assertion: a, Actual: actual}
+		if !ok {
+			res.Error = fmt.Sprintf("field %q not found", a.Field)
+		} else if a.Field == "rebalance_time_seconds" && actual < 0 {
+			res.Error = "rebalance_time_seconds: run never rebalanced"
+		} else {
+			res.Passed = compare(actual, a.Op, a.Value)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// AssertionErrors marks every assertion as not evaluated because of err.
+func AssertionErrors(assertions []Assertion, err error) []AssertionResult {
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		results = append(results, AssertionResult{Assertion: a, Error: "run failed: " + err.Error()})
+	}
+	return results
+}
+
+// FailedAssertions counts results that did not pass.
+func FailedAssertions(results []AssertionResult) int {
+	n := 0
+	for _, r := range results {
+		if !r.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+func compare(actual float64, op string, want float64) bool {
+	switch op {
+	case "<=":
+		return actual <= want
+	case ">=":
+		return actual >= want
+	case "==":
+		return actual == want
+	case "!=":
+		return actual != want
+	case "<":
+		return actual < want
+	case ">":
+		return actual > want
+	}
+	return false
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"testing"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/metrics"
+)
+
+func TestParseAssertion(t *testing.T) {
+	a, err := ParseAssertion("after.pods_stddev <= 1.0")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if a.Field != "after.pods_stddev" || a.Op != "<=" || a.Value != 1 {
+		t.Fatalf("unexpected assertion: %+v", a)
+	}
+	for _, expr := range []string{"evictions", "evictions<=many", "after.pods_stdev<1", "<3"} {
+		if _, err := ParseAssertion(expr); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	summary := Summary{
+		RebalanceTimeSeconds: 90,
+		Before:               metrics.Sample{PodsStddev: 4},
+		After:                metrics.Sample{PodsStddev: 1.4, UnschedulablePods: 0},
+	}
+	assertions, err := ParseAssertions([]string{"after.pods_stddev<=1.0", "rebalance_time_seconds<120", "evictions<=30", "unschedulable_pods==0"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	results := Evaluate(assertions, SummaryValues(summary, 31))
+	passed := []bool{false, true, false, true}
+	for i, res := range results {
+		if res.Passed != passed[i] {
+			t.Fatalf("%s: expected passed=%v, got %+v", res.Expr, passed[i], res)
+		}
+	}
+	if FailedAssertions(results) != 2 {
+		t.Fatalf("expected 2 failures")
+	}
+	if got := results[0].Message(); got != "after.pods_stddev = 1.4, want <= 1" {
+		t.Fatalf("unexpected message %q", got)
+	}
+}
+
+func TestEvaluateNeverRebalanced(t *testing.T) {
+	assertions, err := ParseAssertions([]string{"rebalance_time_seconds<120", "rebalance_time_seconds>=0", "rebalance_time_seconds!=5"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	results := Evaluate(assertions, SummaryValues(Summary{RebalanceTimeSeconds: -1}, 0))
+	for _, res := range results {
+		if res.Passed || res.Error == "" {
+			t.Fatalf("%s: expected an error for a run that never rebalanced, got %+v", res.Expr, res)
+		}
+	}
+	if FailedAssertions(results) != len(assertions) {
+		t.Fatalf("expected every rebalance assertion to fail")
+	}
+	if got := results[0].Message(); got != "rebalance_time_seconds: run never rebalanced" {
+		t.Fatalf("unexpected message %q", got)
+	}
+}
+
+func TestSummaryValuesClusterWide(t *testing.T) {
+	before := metrics.Snapshot{Nodes: map[string]metrics.NodeStats{
+		"w1": {Pods: 6, CPUAllocatableMilli: 1000, CPURequestedMilli: 800},
+		"w2": {Pods: 2, CPUAllocatableMilli: 1000, CPURequestedMilli: 200},
+	}}
+	after := metrics.Snapshot{Nodes: map[string]metrics.NodeStats{
+		"w1": {Pods: 4, CPUAllocatableMilli: 1000, CPURequestedMilli: 500},
+		"w2": {Pods: 4, CPUAllocatableMilli: 1000, CPURequestedMilli: 500},
+	}}
+	summary := Summary{After: metrics.Sample{PodsStddev: 0.5}, ClusterWide: NewClusterBalance(before, after)}
+	values := SummaryValues(summary, 0)
+	if values["cluster_wide.before.pods_stddev"] != 2 || values["cluster_wide.after.pods_stddev"] != 0 {
+		t.Fatalf("unexpected cluster-wide pods stddev: %v", values)
+	}
+	if values["cluster_wide.before_cpu_requested_pct_stddev"] != 30 {
+		t.Fatalf("unexpected cluster-wide cpu stddev: %v", values["cluster_wide.before_cpu_requested_pct_stddev"])
+	}
+	if _, err := ParseAssertion("cluster_wide.after_cpu_requested_pct_stddev<=10"); err != nil {
+		t.Fatalf("expected cluster-wide fields to be accepted: %v", err)
+	}
+	if values["pods_stddev"] != 0.5 {
+		t.Fatalf("expected the after alias to keep the benchmark-only value, got %v", values["pods_stddev"])
+	}
+}
+
+func TestSummaryValuesPriority(t *testing.T) {
+	summary := Summary{Priority: NewPrioritySummary([]k8s.Disruption{
+		{Trigger: k8s.TriggerDrain, Cause: k8s.CauseEvicted, Priority: "low"},
+		{Trigger: k8s.TriggerDrain, Cause: k8s.CauseEvicted, Priority: "low"},
+		{Trigger: k8s.TriggerDrain, Cause: k8s.CausePreempted, Priority: "high"},
+		{Trigger: k8s.TriggerDescheduler, Cause: k8s.CauseEvicted, Priority: ""},
+	})}
+	values := SummaryValues(summary, 0)
+	if values["priority.drain_evicted.low"] != 2 || values["priority.drain_preempted.high"] != 1 || values["priority.descheduler_evicted.none"] != 1 {
+		t.Fatalf("unexpected priority values: %v", values)
+	}
+	if values["priority.descheduler_evicted.critical"] != 0 {
+		t.Fatalf("expected no critical evictions, got %v", values["priority.descheduler_evicted.critical"])
+	}
+	if _, err := ParseAssertion("priority.descheduler_evicted.critical==0"); err != nil {
+		t.Fatalf("expected priority fields to be accepted: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package report
+
+type ImageRun struct {
+	Image      string  `json:"image"`
+	ImageID    string  `json:"image_id"`
+	OutputPath string  `json:"output_path"`
+	Summary    Summary `json:"summary"`
+	Evictions  int     `json:"evictions"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type ImageComparison struct {
+	Scenario       string               `json:"scenario"`
+	Profile        string               `json:"profile"`
+	ReferenceImage string               `json:"reference_image"`
+	Rows           []ImageComparisonRow `json:"rows"`
+}
+
+type ImageComparisonRow struct {
+	ImageRun
+	AfterStddevDelta   float64 `json:"after_pods_stddev_delta"`
+	RebalanceTimeDelta float64 `json:"rebalance_time_seconds_delta"`
+	DurationDelta      float64 `json:"duration_seconds_delta"`
+	EvictionsDelta     int     `json:"evictions_delta"`
+}
+
+// CompareImages lines up runs of the same scenario against different
+// descheduler images. Deltas are relative to the first successful run.
+func CompareImages(scenario, profile string, runs []ImageRun) ImageComparison {
+	out := ImageComparison{Scenario: scenario, Profile: profile}
+	var ref *ImageRun
+	for i := range runs {
+		if runs[i].Error == "" {
+			ref = &runs[i]
+			break
+		}
+	}
+	if ref != nil {
+		out.ReferenceImage = ref.Image
+	}
+	for _, run := range runs {
+		row := ImageComparisonRow{ImageRun: run}
+		if ref != nil && run.Error == "" {
+			row.AfterStddevDelta = run.Summary.After.PodsStddev - ref.Summary.After.PodsStddev
+			row.RebalanceTimeDelta = run.Summary.RebalanceTimeSeconds - ref.Summary.RebalanceTimeSeconds
+			row.DurationDelta = run.Summary.DurationSeconds - ref.Summary.DurationSeconds
+			row.EvictionsDelta = run.Evictions - ref.Evictions
+		}
+		out.Rows = append(out.Rows, row)
+	}
+	return out
+}
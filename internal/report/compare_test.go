@@ -0,0 +1,29 @@
+package report
+
+import (
+	"testing"
+
+	"k8s-descheduler-benchmark/internal/metrics"
+)
+
+func TestCompareImages(t *testing.T) {
+	runs := []ImageRun{
+		{Image: "broken", Error: "boom"},
+		{Image: "v0.31.0", Evictions: 10, Summary: Summary{After: metrics.Sample{PodsStddev: 2}, RebalanceTimeSeconds: 30}},
+		{Image: "v0.32.2", Evictions: 6, Summary: Summary{After: metrics.Sample{PodsStddev: 1.5}, RebalanceTimeSeconds: 20}},
+	}
+	got := CompareImages("maintenance", "low-node-utilization", runs)
+	if got.ReferenceImage != "v0.31.0" {
+		t.Fatalf("expected first successful run as reference, got %q", got.ReferenceImage)
+	}
+	if len(got.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(got.Rows))
+	}
+	row := got.Rows[2]
+	if row.AfterStddevDelta != -0.5 || row.RebalanceTimeDelta != -10 || row.EvictionsDelta != -4 {
+		t.Fatalf("unexpected deltas: %+v", row)
+	}
+	if got.Rows[0].EvictionsDelta != 0 {
+		t.Fatalf("expected no deltas for failed run")
+	}
+}
@@ -0,0 +1,210 @@
+package report
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats besides the JSON result itself.
+const (
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Table is a flat view of one part of a result. Every row starts with the
+// run_id and profile columns so tables from several runs can be stacked.
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+// ParseFormats splits a comma-separated format list and rejects unknown
+// names. JSON is always written, so it is accepted and dropped.
+func ParseFormats(value string) ([]string, error) {
+	var out []string
+	for _, f := range strings.Split(value, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		switch f {
+		case "", FormatJSON:
+		case FormatCSV, FormatNDJSON:
+			out = append(out, f)
+		default:
+			return nil, fmt.Errorf("unsupported format %q (want json, csv or ndjson)", f)
+		}
+	}
+	return out, nil
+}
+
+// ExportDir is the directory exports of the result at path go to:
+// results/descheduler.json exports to results/descheduler/.
+func ExportDir(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path))
+}
+
+// Tables flattens r into samples, phases, evictions, node_samples,
+// pending_reasons and disruptions. The node_samples table has one row per node per sampler
+// snapshot and pending_reasons one row per reason per sample.
+func Tables(r Result) []Table {
+	run := []any{r.Config.RunID, r.Config.Profile}
+	row := func(values ...any) []any {
+		return append(append([]any{}, run...), values...)
+	}
+
+	samples := Table{Name: "samples", Columns: []string{
+		"run_id", "profile", "time", "pods_stddev", "pods_max_min_ratio", "unschedulable_pods", "nodes_count", "pods_counted",
+	}}
+	for _, s := range r.Samples {
+		samples.Rows = append(samples.Rows, row(s.Time, s.PodsStddev, s.PodsMaxMinRatio, s.UnschedulablePods, s.NodesCount, s.PodsCounted))
+	}
+
+	phases := Table{Name: "phases", Columns: []string{"run_id", "profile", "phase", "time", "offset_seconds"}}
+	for _, p := range r.Phases {
+		phases.Rows = append(phases.Rows, row(p.Name, p.Time, p.Time.Sub(r.Phases[0].Time).Seconds()))
+	}
+
+	evictions := Table{Name: "evictions", Columns: []string{
+		"run_id", "profile", "pod_name", "app_label", "node_name", "reason", "message", "evicted_at", "rescheduled_at", "reschedule_seconds", "kind",
+	}}
+	for _, e := range r.Evictions {
+		evictions.Rows = append(evictions.Rows, row(e.PodName, e.AppLabel, e.NodeName, e.Reason, e.Message, e.EvictedAt, e.RescheduledAt, e.RescheduleSeconds, e.Kind))
+	}
+
+	nodes := Table{Name: "node_samples", Columns: []string{
+		"run_id", "profile", "time", "node", "pods", "cpu_requested_milli", "mem_requested_bytes", "cpu_allocatable_milli", "mem_allocatable_bytes",
+	}}
+	for _, snap := range r.Snapshots {
+		names := make([]string, 0, len(snap.Nodes))
+		for name := range snap.Nodes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			n := snap.Nodes[name]
+			nodes.Rows = append(nodes.Rows, row(snap.Time, name, n.Pods, n.CPURequestedMilli, n.MemRequestedBytes, n.CPUAllocatableMilli, n.MemAllocatableBytes))
+		}
+	}
+
+	pending := Table{Name: "pending_reasons", Columns: []string{"run_id", "profile", "time", "reason", "message", "pods"}}
+	for _, s := range r.Samples {
+		for _, p := range s.PendingReasons {
+			pending.Rows = append(pending.Rows, row(s.Time, p.Reason, p.Message, p.Pods))
+		}
+	}
+
+	disruptions := Table{Name: "disruptions", Columns: []string{
+		"run_id", "profile", "time", "trigger", "iteration", "cause", "priority", "pod_name", "node_name", "message",
+	}}
+	for _, d := range r.Disruptions {
+		disruptions.Rows = append(disruptions.Rows, row(d.Time, d.Trigger, d.Iteration, d.Cause, d.Priority, d.PodName, d.NodeName, d.Message))
+	}
+
+	return []Table{samples, phases, evictions, nodes, pending, disruptions}
+}
+
+// Export writes every table of r to dir as <table>.<format> and returns the
+// paths written.
+func Export(dir string, r Result, format string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, table := range Tables(r) {
+		path := filepath.Join(dir, table.Name+"."+format)
+		var err error
+		switch format {
+		case FormatCSV:
+			err = writeFile(path, func(w *bufio.Writer) error { return writeCSV(w, table) })
+		case FormatNDJSON:
+			err = writeFile(path, func(w *bufio.Writer) error { return writeNDJSON(w, table) })
+		default:
+			err = fmt.Errorf("unsupported export format %q", format)
+		}
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func writeFile(path string, write func(*bufio.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	if err := write(w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func writeCSV(w *bufio.Writer, table Table) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(table.Columns); err != nil {
+		return err
+	}
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, value := range row {
+			record[i] = formatCell(value)
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeNDJSON writes one object per row, keys in column order.
+func writeNDJSON(w *bufio.Writer, table Table) error {
+	for _, row := range table.Rows {
+		w.WriteByte('{')
+		for i, value := range row {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			key, _ := json.Marshal(table.Columns[i])
+			w.Write(key)
+			w.WriteByte(':')
+			if t, ok := value.(time.Time); ok && t.IsZero() {
+				value = nil
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			w.Write(data)
+		}
+		w.WriteString("}\n")
+	}
+	return nil
+}
+
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,127 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/metrics"
+)
+
+func exportFixture() Result {
+	t0 := time.Date(2026, 2, 9, 2, 50, 0, 0, time.UTC)
+	return Result{
+		Config: RunConfig{RunID: "run-a", Profile: "baseline"},
+		Phases: []PhaseMarker{{Name: "workload:create", Time: t0}, {Name: "drain:done", Time: t0.Add(1500 * time.Millisecond)}},
+		Samples: []metrics.Sample{
+			{Time: t0, PodsStddev: 0.5, NodesCount: 2, PodsCounted: 10, PendingReasons: []k8s.PendingReason{
+				{Reason: "Unschedulable", Message: "Insufficient cpu, node(s) were unschedulable", Pods: 3},
+			}},
+		},
+		Snapshots: []metrics.Snapshot{{Time: t0, Nodes: map[string]metrics.NodeStats{
+			"w2": {Pods: 4, CPURequestedMilli: 400},
+			"w1": {Pods: 6, CPURequestedMilli: 600},
+		}}},
+		Evictions: []k8s.EvictionRecord{{PodName: "p-1", NodeName: "w1", Message: "evicted, low utilization", EvictedAt: t0}},
+		Disruptions: []k8s.Disruption{
+			{PodName: "p-2", NodeName: "w2", Priority: "low", Cause: k8s.CausePreempted, Trigger: k8s.TriggerDrain, Iteration: 1, Time: t0},
+		},
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := Export(dir, exportFixture(), FormatCSV)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if len(paths) != 6 {
+		t.Fatalf("expected 6 tables, got %v", paths)
+	}
+
+	read := func(name string) [][]string {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return records
+	}
+
+	nodes := read("node_samples.csv")
+	if len(nodes) != 3 || nodes[1][3] != "w1" || nodes[1][4] != "6" || nodes[2][3] != "w2" {
+		t.Fatalf("unexpected node_samples: %v", nodes)
+	}
+	phases := read("phases.csv")
+	if phases[2][2] != "drain:done" || phases[2][4] != "1.5" {
+		t.Fatalf("unexpected phases: %v", phases)
+	}
+	evictions := read("evictions.csv")
+	if evictions[1][6] != "evicted, low utilization" || evictions[1][8] != "" {
+		t.Fatalf("unexpected evictions: %v", evictions)
+	}
+	pending := read("pending_reasons.csv")
+	if len(pending) != 2 || pending[1][4] != "Insufficient cpu, node(s) were unschedulable" || pending[1][5] != "3" {
+		t.Fatalf("unexpected pending_reasons: %v", pending)
+	}
+	disruptions := read("disruptions.csv")
+	if len(disruptions) != 2 || disruptions[1][3] != "drain" || disruptions[1][5] != "preempted" || disruptions[1][6] != "low" {
+		t.Fatalf("unexpected disruptions: %v", disruptions)
+	}
+	samples := read("samples.csv")
+	if samples[0][0] != "run_id" || samples[1][0] != "run-a" || samples[1][2] != "2026-02-09T02:50:00Z" {
+		t.Fatalf("unexpected samples: %v", samples)
+	}
+}
+
+func TestExportNDJSON(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Export(dir, exportFixture(), FormatNDJSON); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "node_samples.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per node, got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], `{"run_id":"run-a","profile":"baseline","time":`) {
+		t.Fatalf("expected keys in column order, got %s", lines[0])
+	}
+	var row map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &row); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if row["node"] != "w1" || row["pods"] != float64(6) {
+		t.Fatalf("unexpected row: %v", row)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "evictions.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"rescheduled_at":null`) {
+		t.Fatalf("expected zero time as null: %s", data)
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats("json, CSV,ndjson")
+	if err != nil || len(formats) != 2 || formats[0] != FormatCSV || formats[1] != FormatNDJSON {
+		t.Fatalf("unexpected formats %v (err %v)", formats, err)
+	}
+	if _, err := ParseFormats("parquet"); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
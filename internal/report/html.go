@@ -0,0 +1,137 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed templates/report.html.tmpl
+var htmlTemplate string
+
+// HTMLRun is one result file shown in the HTML report.
+type HTMLRun struct {
+	Source string
+	Result Result
+}
+
+type htmlPage struct {
+	Generated time.Time
+	Runs      []htmlRunView
+}
+
+type htmlRunView struct {
+	ID         string
+	Source     string
+	Result     Result
+	Config     [][2]string
+	Balance    template.HTML
+	Nodes      template.HTML
+	Evictions  template.HTML
+	Reschedule template.HTML
+}
+
+// WriteHTML renders runs as a single self-contained HTML page: inline CSS
+// and SVG charts, no scripts or external assets.
+func WriteHTML(w io.Writer, runs []HTMLRun) error {
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"seconds":   func(v float64) string { return fmt.Sprintf("%.1fs", v) },
+		"float":     func(v float64) string { return fmt.Sprintf("%.2f", v) },
+		"timestamp": func(t time.Time) string { return t.Format(time.RFC3339) },
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+	page := htmlPage{Generated: time.Now()}
+	for i, run := range runs {
+		page.Runs = append(page.Runs, runView(i, run))
+	}
+	return tmpl.Execute(w, page)
+}
+
+func runView(i int, run HTMLRun) htmlRunView {
+	r := run.Result
+	view := htmlRunView{
+		ID:     fmt.Sprintf("run-%d", i+1),
+		Source: run.Source,
+		Result: r,
+		Config: configRows(r.Config),
+	}
+
+	stddev := chartSeries{Name: "pods stddev"}
+	unschedulable := chartSeries{Name: "unschedulable pods"}
+	for _, s := range r.Samples {
+		stddev.Times = append(stddev.Times, s.Time)
+		stddev.Values = append(stddev.Values, s.PodsStddev)
+		unschedulable.Times = append(unschedulable.Times, s.Time)
+		unschedulable.Values = append(unschedulable.Values, float64(s.UnschedulablePods))
+	}
+	view.Balance = lineChart([]chartSeries{stddev, unschedulable}, r.Phases)
+
+	nodeSet := map[string]struct{}{}
+	for name := range r.BeforeSnapshot.Nodes {
+		nodeSet[name] = struct{}{}
+	}
+	for name := range r.AfterSnapshot.Nodes {
+		nodeSet[name] = struct{}{}
+	}
+	nodes := make([]string, 0, len(nodeSet))
+	for name := range nodeSet {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+	var groups []barGroup
+	for _, name := range nodes {
+		groups = append(groups, barGroup{Label: name, Values: []float64{
+			float64(r.BeforeSnapshot.Nodes[name].Pods),
+			float64(r.AfterSnapshot.Nodes[name].Pods),
+		}})
+	}
+	view.Nodes = barChart(groups, []string{"before", "after"})
+
+	var events []timelineEvent
+	var latencies []float64
+	for _, e := range r.Evictions {
+		label := e.PodName
+		if e.Reason != "" {
+			label += " (" + e.Reason + ")"
+		}
+		events = append(events, timelineEvent{Row: e.NodeName, Time: e.EvictedAt, Label: label})
+		if !e.RescheduledAt.IsZero() {
+			latencies = append(latencies, e.RescheduleSeconds)
+		}
+	}
+	view.Evictions = timeline(events, r.Phases)
+	view.Reschedule = histogram(latencies, 10, "s")
+	return view
+}
+
+// configRows lists the non-empty RunConfig fields under their JSON names.
+func configRows(cfg RunConfig) [][2]string {
+	var rows [][2]string
+	v := reflect.ValueOf(cfg)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		value := v.Field(i).Interface()
+		var text string
+		switch val := value.(type) {
+		case time.Time:
+			if !val.IsZero() {
+				text = val.Format(time.RFC3339)
+			}
+		default:
+			text = fmt.Sprint(val)
+		}
+		if text == "" || text == "0" {
+			continue
+		}
+		rows = append(rows, [2]string{name, text})
+	}
+	return rows
+}
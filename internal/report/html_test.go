@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/metrics"
+)
+
+func TestWriteHTML(t *testing.T) {
+	r := exportFixture()
+	r.Status = StatusSuccess
+	r.BeforeSnapshot = metrics.Snapshot{Nodes: map[string]metrics.NodeStats{"w1": {Pods: 6}, "w2": {Pods: 4}}}
+	r.AfterSnapshot = metrics.Snapshot{Nodes: map[string]metrics.NodeStats{"w1": {Pods: 5}, "w2": {Pods: 5}}}
+	r.Evictions = append(r.Evictions, k8s.EvictionRecord{
+		PodName:           "<p-2>",
+		NodeName:          "w1",
+		EvictedAt:         r.Phases[0].Time,
+		RescheduledAt:     r.Phases[0].Time.Add(3 * time.Second),
+		RescheduleSeconds: 3,
+	})
+	r.Summary.ClusterWide = NewClusterBalance(r.BeforeSnapshot, r.AfterSnapshot)
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, []HTMLRun{{Source: "results/a.json", Result: r}, {Source: "results/b.json", Result: Result{}}}); err != nil {
+		t.Fatalf("write html: %v", err)
+	}
+	out := buf.String()
+
+	if strings.Contains(out, "<script") {
+		t.Fatalf("report must not contain scripts")
+	}
+	if n := strings.Count(out, "<svg "); n != 8 {
+		t.Fatalf("expected 4 charts per run, got %d", n)
+	}
+	if strings.Contains(out, "<p-2>") || !strings.Contains(out, "&lt;p-2&gt;") {
+		t.Fatalf("expected pod names to be escaped")
+	}
+	if n := strings.Count(out, "Cluster-wide balance"); n != 1 {
+		t.Fatalf("expected the cluster-wide table only for the run that has it, got %d", n)
+	}
+	for _, want := range []string{`href="#run-2"`, "results/b.json", "no evictions", "<th>run_id</th><td>run-a</td>", "drain:done"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected report to contain %q", want)
+		}
+	}
+}
+
+func TestNiceCeil(t *testing.T) {
+	for v, want := range map[float64]float64{0.3: 0.5, 1: 1, 1.2: 2, 23: 50, 60: 100} {
+		if got := niceCeil(v); got != want {
+			t.Fatalf("niceCeil(%v) = %v, want %v", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+// WriteJUnit writes the assertion results of one run as a JUnit XML report
+// with one test case per assertion. Failed thresholds are failures;
+// assertions that could not be evaluated are errors.
+func WriteJUnit(path string, summary Summary, start time.Time, results []AssertionResult) error {
+	suite := junitSuite{
+		Name: summary.Scenario + "/" + summary.Profile,
+		Time: formatValue(summary.DurationSeconds),
+		Properties: []junitProperty{
+			{Name: "run_id", Value: summary.RunID},
+			{Name: "scenario", Value: summary.Scenario},
+			{Name: "profile", Value: summary.Profile},
+		},
+	}
+	if !start.IsZero() {
+		suite.Timestamp = start.UTC().Format(time.RFC3339)
+	}
+	for _, r := range results {
+		c := junitCase{
+			Name:      r.Expr,
+			Classname: "deschedbench." + summary.Scenario + "." + summary.Profile,
+			Time:      "0",
+		}
+		switch {
+		case r.Error != "":
+			c.Error = &junitMessage{Message: r.Message(), Type: "error"}
+			suite.Errors++
+		case !r.Passed:
+			c.Failure = &junitMessage{Message: r.Message(), Type: "assertion"}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, c)
+	}
+	doc := junitSuites{
+		Name:     "deschedbench",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Suites:   []junitSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), append(data, '\n')...), 0o644)
+}
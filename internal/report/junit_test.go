@@ -0,0 +1,46 @@
+package report
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	assertions, err := ParseAssertions([]string{"evictions<=30", "after.pods_stddev<=1", "rebalance_time_seconds<120"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := Evaluate(assertions[:2], map[string]float64{"evictions": 12, "after.pods_stddev": 2})
+	results = append(results, AssertionErrors(assertions[2:], errors.New("drain timed out"))...)
+	summary := Summary{RunID: "run-a", Scenario: "maintenance", Profile: "baseline", DurationSeconds: 42}
+
+	path := filepath.Join(t.TempDir(), "junit", "report.xml")
+	if err := WriteJUnit(path, summary, time.Date(2026, 2, 9, 2, 50, 0, 0, time.UTC), results); err != nil {
+		t.Fatalf("write junit: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc junitSuites
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decode: %v\n%s", err, data)
+	}
+	if doc.Tests != 3 || doc.Failures != 1 || doc.Errors != 1 || len(doc.Suites) != 1 {
+		t.Fatalf("unexpected totals: %+v", doc)
+	}
+	suite := doc.Suites[0]
+	if suite.Name != "maintenance/baseline" || suite.Time != "42" || suite.Timestamp != "2026-02-09T02:50:00Z" {
+		t.Fatalf("unexpected suite: %+v", suite)
+	}
+	if suite.Cases[0].Failure != nil || suite.Cases[1].Failure == nil || suite.Cases[2].Error == nil {
+		t.Fatalf("unexpected cases: %+v", suite.Cases)
+	}
+	if suite.Cases[2].Error.Message != "run failed: drain timed out" {
+		t.Fatalf("unexpected error message %q", suite.Cases[2].Error.Message)
+	}
+}
@@ -0,0 +1,108 @@
+package report
+
+import (
+	"math"
+	"sort"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+)
+
+// LatencyStats summarizes durations in seconds with nearest-rank
+// percentiles.
+type LatencyStats struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+// TriggerLatency breaks down the startup of the pods created for one
+// trigger: the initial rollout, replacements after drains, or replacements
+// after descheduler evictions.
+type TriggerLatency struct {
+	Trigger             string       `json:"trigger"`
+	Pods                int          `json:"pods"`
+	CreationToScheduled LatencyStats `json:"creation_to_scheduled"`
+	ScheduledToRunning  LatencyStats `json:"scheduled_to_running"`
+	RunningToReady      LatencyStats `json:"running_to_ready"`
+	CreationToReady     LatencyStats `json:"creation_to_ready"`
+}
+
+// LatencyReport separates scheduler time from container start time.
+type LatencyReport struct {
+	Triggers []TriggerLatency `json:"triggers"`
+	// Reschedule summarizes EvictionRecord.RescheduleSeconds for the
+	// evictions that were followed by a ready replacement.
+	Reschedule LatencyStats     `json:"reschedule"`
+	Pods       []k8s.PodLatency `json:"pods"`
+}
+
+// SummarizeLatency groups pods by trigger, in initial, drain, descheduler
+// order. Stages a pod has not reached are left out of the percentiles.
+func SummarizeLatency(pods []k8s.PodLatency, evictions []k8s.EvictionRecord) *LatencyReport {
+	if len(pods) == 0 && len(evictions) == 0 {
+		return nil
+	}
+	out := &LatencyReport{Pods: pods}
+	for _, trigger := range []string{k8s.TriggerInitial, k8s.TriggerDrain, k8s.TriggerDescheduler} {
+		var scheduling, start, ready, total []float64
+		count := 0
+		for _, p := range pods {
+			if p.Trigger != trigger {
+				continue
+			}
+			count++
+			scheduling = appendKnown(scheduling, p.SchedulingSeconds)
+			start = appendKnown(start, p.StartSeconds)
+			ready = appendKnown(ready, p.ReadySeconds)
+			if !p.ReadyAt.IsZero() {
+				total = append(total, math.Max(p.ReadyAt.Sub(p.CreatedAt).Seconds(), 0))
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		out.Triggers = append(out.Triggers, TriggerLatency{
+			Trigger:             trigger,
+			Pods:                count,
+			CreationToScheduled: Percentiles(scheduling),
+			ScheduledToRunning:  Percentiles(start),
+			RunningToReady:      Percentiles(ready),
+			CreationToReady:     Percentiles(total),
+		})
+	}
+	var reschedule []float64
+	for _, e := range evictions {
+		reschedule = appendKnown(reschedule, e.RescheduleSeconds)
+	}
+	out.Reschedule = Percentiles(reschedule)
+	return out
+}
+
+// appendKnown skips the -1 used for stages that were never reached.
+func appendKnown(values []float64, v float64) []float64 {
+	if v < 0 {
+		return values
+	}
+	return append(values, v)
+}
+
+// Percentiles returns nearest-rank p50, p90 and p99 and the max of values.
+func Percentiles(values []float64) LatencyStats {
+	if len(values) == 0 {
+		return LatencyStats{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := func(p float64) float64 {
+		return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+	}
+	return LatencyStats{
+		Count: len(sorted),
+		P50:   rank(0.50),
+		P90:   rank(0.90),
+		P99:   rank(0.99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
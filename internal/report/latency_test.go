@@ -0,0 +1,66 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+)
+
+func TestPercentiles(t *testing.T) {
+	values := make([]float64, 0, 100)
+	for i := 100; i >= 1; i-- {
+		values = append(values, float64(i))
+	}
+	got := Percentiles(values)
+	if got.Count != 100 || got.P50 != 50 || got.P90 != 90 || got.P99 != 99 || got.Max != 100 {
+		t.Fatalf("unexpected percentiles: %+v", got)
+	}
+	if values[0] != 100 {
+		t.Fatalf("expected input to be left unsorted")
+	}
+	if got := Percentiles(nil); got.Count != 0 {
+		t.Fatalf("expected empty stats, got %+v", got)
+	}
+}
+
+func TestSummarizeLatency(t *testing.T) {
+	t0 := time.Date(2026, 2, 9, 2, 50, 0, 0, time.UTC)
+	pods := []k8s.PodLatency{
+		{PodName: "a", Trigger: k8s.TriggerInitial, CreatedAt: t0, ReadyAt: t0.Add(4 * time.Second), SchedulingSeconds: 1, StartSeconds: 2, ReadySeconds: 1},
+		{PodName: "b", Trigger: k8s.TriggerDescheduler, CreatedAt: t0, SchedulingSeconds: 8, StartSeconds: -1, ReadySeconds: -1},
+		{PodName: "c", Trigger: k8s.TriggerDescheduler, CreatedAt: t0, ReadyAt: t0.Add(5 * time.Second), SchedulingSeconds: 2, StartSeconds: 2, ReadySeconds: 1},
+	}
+	evictions := []k8s.EvictionRecord{{RescheduleSeconds: 6}, {RescheduleSeconds: -1}}
+
+	got := SummarizeLatency(pods, evictions)
+	if len(got.Triggers) != 2 || got.Triggers[0].Trigger != k8s.TriggerInitial || got.Triggers[1].Trigger != k8s.TriggerDescheduler {
+		t.Fatalf("expected initial and descheduler triggers only, got %+v", got.Triggers)
+	}
+	d := got.Triggers[1]
+	if d.Pods != 2 || d.CreationToScheduled.Max != 8 || d.ScheduledToRunning.Count != 1 || d.CreationToReady.P50 != 5 {
+		t.Fatalf("unexpected descheduler breakdown: %+v", d)
+	}
+	if got.Reschedule.Count != 1 || got.Reschedule.Max != 6 {
+		t.Fatalf("expected rescheduled evictions only, got %+v", got.Reschedule)
+	}
+	if SummarizeLatency(nil, nil) != nil {
+		t.Fatalf("expected nil report without data")
+	}
+}
+
+func TestCountStranded(t *testing.T) {
+	evictions := []k8s.EvictionRecord{
+		{PodName: "a", Kind: k8s.KindDeployment, RescheduleSeconds: 3},
+		{PodName: "b", Kind: k8s.KindPod, RescheduleSeconds: -1},
+		{PodName: "c", Kind: k8s.KindStatefulSet, RescheduleSeconds: -1},
+		{PodName: "d", Kind: k8s.KindStatefulSet, RescheduleSeconds: 5},
+	}
+	total, stateful := CountStranded(evictions)
+	if total != 2 || stateful != 1 {
+		t.Fatalf("expected 2 stranded, 1 stateful; got %d, %d", total, stateful)
+	}
+	if _, err := ParseAssertion("stranded_stateful_pods==0"); err != nil {
+		t.Fatalf("expected stranded_stateful_pods to be assertable: %v", err)
+	}
+}
@@ -3,9 +3,18 @@ package report
 import (
 	"time"
 
+	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/metrics"
 )
 
+// Run status values recorded in result files.
+const (
+	StatusSuccess     = "success"
+	StatusFailed      = "failed"
+	StatusCancelled   = "cancelled"
+	StatusInterrupted = "interrupted"
+)
+
 type RunConfig struct {
 	RunID                string    `json:"run_id"`
 	Scenario             string    `json:"scenario"`
@@ -18,10 +27,23 @@ type RunConfig struct {
 	PodCPU               string    `json:"pod_cpu"`
 	PodMemory            string    `json:"pod_memory"`
 	DeschedulerImage     string    `json:"descheduler_image"`
+	DeschedulerImageID   string    `json:"descheduler_image_id"`
 	DeschedulerNamespace string    `json:"descheduler_namespace"`
+	DeschedulerMode      string    `json:"descheduler_mode"`
 	DeschedulerCron      string    `json:"descheduler_cron"`
+	DeschedulerInterval  string    `json:"descheduler_interval"`
 	SampleInterval       string    `json:"sample_interval"`
 	SampleDuration       string    `json:"sample_duration"`
+	// NoiseNamespaces and NoiseMix describe the background workloads run
+	// next to the benchmark, per namespace.
+	NoiseNamespaces int    `json:"noise_namespaces,omitempty"`
+	NoiseMix        string `json:"noise_mix,omitempty"`
+	// Mix is the benchmark workload mix when it was given with --mix,
+	// such as "small=40,sts/small=10".
+	Mix string `json:"mix,omitempty"`
+	// PriorityThreshold is the DefaultEvictor priorityThreshold value the
+	// policy was run with; pods at or above it are never evicted.
+	PriorityThreshold int32 `json:"priority_threshold,omitempty"`
 }
 
 type PhaseMarker struct {
@@ -37,4 +59,111 @@ type Summary struct {
 	RebalanceTimeSeconds float64        `json:"rebalance_time_seconds"`
 	Before               metrics.Sample `json:"before"`
 	After                metrics.Sample `json:"after"`
+	// ClusterWide is the balance with the pods of every namespace counted.
+	ClusterWide *ClusterBalance `json:"cluster_wide,omitempty"`
+	// StrandedPods counts evicted pods without a ready replacement when
+	// the run ended; StrandedStatefulPods is the StatefulSet share of it.
+	StrandedPods         int `json:"stranded_pods"`
+	StrandedStatefulPods int `json:"stranded_stateful_pods"`
+	// Priority breaks disruptions down by priority tier; set when the mix
+	// uses priority tiers.
+	Priority *PrioritySummary `json:"priority,omitempty"`
+}
+
+// PrioritySummary counts disrupted benchmark pods per priority tier, by
+// what was running (drain or descheduler pass) and how the pod went.
+type PrioritySummary struct {
+	DrainEvicted         TierCounts `json:"drain_evicted"`
+	DrainPreempted       TierCounts `json:"drain_preempted"`
+	DeschedulerEvicted   TierCounts `json:"descheduler_evicted"`
+	DeschedulerPreempted TierCounts `json:"descheduler_preempted"`
+}
+
+// TierCounts counts pods per priority tier. None counts pods without a
+// tier.
+type TierCounts struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Low      int `json:"low"`
+	None     int `json:"none"`
+}
+
+func (c *TierCounts) add(tier string) {
+	switch tier {
+	case "critical":
+		c.Critical++
+	case "high":
+		c.High++
+	case "low":
+		c.Low++
+	default:
+		c.None++
+	}
+}
+
+// NewPrioritySummary tallies disruptions by trigger, cause and tier.
+func NewPrioritySummary(disruptions []k8s.Disruption) *PrioritySummary {
+	out := &PrioritySummary{}
+	for _, d := range disruptions {
+		var counts *TierCounts
+		switch {
+		case d.Trigger == k8s.TriggerDrain && d.Cause == k8s.CauseEvicted:
+			counts = &out.DrainEvicted
+		case d.Trigger == k8s.TriggerDrain && d.Cause == k8s.CausePreempted:
+			counts = &out.DrainPreempted
+		case d.Trigger == k8s.TriggerDescheduler && d.Cause == k8s.CauseEvicted:
+			counts = &out.DeschedulerEvicted
+		case d.Trigger == k8s.TriggerDescheduler && d.Cause == k8s.CausePreempted:
+			counts = &out.DeschedulerPreempted
+		default:
+			continue
+		}
+		counts.add(d.Priority)
+	}
+	return out
+}
+
+// CountStranded returns how many evictions stranded their pod, in total and
+// for StatefulSet pods.
+func CountStranded(evictions []k8s.EvictionRecord) (total, stateful int) {
+	for _, e := range evictions {
+		if !e.Stranded() {
+			continue
+		}
+		total++
+		if e.Kind == k8s.KindStatefulSet {
+			stateful++
+		}
+	}
+	return total, stateful
+}
+
+// ClusterBalance is the balance of all pods on the cluster, noise
+// workloads and system pods included, next to the benchmark-only Before and
+// After of Summary.
+type ClusterBalance struct {
+	Before metrics.Sample `json:"before"`
+	After  metrics.Sample `json:"after"`
+	// CPU requested as a percentage of allocatable, stddev across nodes.
+	BeforeCPURequestedStddev float64 `json:"before_cpu_requested_pct_stddev"`
+	AfterCPURequestedStddev  float64 `json:"after_cpu_requested_pct_stddev"`
+}
+
+// NewClusterBalance derives the cluster-wide balance from the cluster
+// snapshots taken at the before and after phases.
+func NewClusterBalance(before, after metrics.Snapshot) *ClusterBalance {
+	return &ClusterBalance{
+		Before:                   metrics.DeriveSample(before),
+		After:                    metrics.DeriveSample(after),
+		BeforeCPURequestedStddev: metrics.CPURequestedStddev(before),
+		AfterCPURequestedStddev:  metrics.CPURequestedStddev(after),
+	}
+}
+
+// Diagnosis explains why benchmark pods were not ready when a run failed.
+type Diagnosis struct {
+	Ready              int32          `json:"ready"`
+	Pending            int32          `json:"pending"`
+	Reasons            map[string]int `json:"reasons"`
+	UnschedulableNodes []string       `json:"unschedulable_nodes,omitempty"`
 }
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"k8s-descheduler-benchmark/internal/descheduler"
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/metrics"
+	"k8s-descheduler-benchmark/internal/promquery"
+)
+
+// CurrentSchemaVersion is the schema_version written by this build.
+// Version 1 is the unversioned layout written before schema_version and
+// status existed; LoadResult migrates it.
+const CurrentSchemaVersion = 2
+
+// Result is the document a benchmark run writes to its output file.
+type Result struct {
+	SchemaVersion int              `json:"schema_version"`
+	Status        string           `json:"status"`
+	Error         string           `json:"error,omitempty"`
+	FailedPhase   string           `json:"failed_phase,omitempty"`
+	Diagnosis     *Diagnosis       `json:"diagnosis,omitempty"`
+	Config        RunConfig        `json:"config"`
+	Phases        []PhaseMarker    `json:"phases"`
+	Summary       Summary          `json:"summary"`
+	Samples       []metrics.Sample `json:"samples"`
+	// Snapshots are the per-node sampler snapshots behind Samples.
+	Snapshots      []metrics.Snapshot     `json:"snapshots,omitempty"`
+	BeforeSnapshot metrics.Snapshot       `json:"before_snapshot"`
+	AfterSnapshot  metrics.Snapshot       `json:"after_snapshot"`
+	Evictions      []k8s.EvictionRecord   `json:"evictions"`
+	Activity       []descheduler.Activity `json:"descheduler_activity"`
+	Prometheus     *promquery.Enrichment  `json:"prometheus,omitempty"`
+	// Latency breaks pod startup into scheduling, container start and
+	// readiness, per trigger.
+	Latency *LatencyReport `json:"latency,omitempty"`
+	// Assertions holds the --assert results when any were given.
+	Assertions []AssertionResult `json:"assertions,omitempty"`
+	// ClusterBeforeSnapshot and ClusterAfterSnapshot count the pods of
+	// every namespace, where BeforeSnapshot and AfterSnapshot only count
+	// the benchmark namespace.
+	ClusterBeforeSnapshot *metrics.Snapshot `json:"cluster_before_snapshot,omitempty"`
+	ClusterAfterSnapshot  *metrics.Snapshot `json:"cluster_after_snapshot,omitempty"`
+	// Disruptions lists the benchmark pods evicted or preempted during
+	// drains and descheduler passes, with their priority tier.
+	Disruptions []k8s.Disruption `json:"disruptions,omitempty"`
+}
+
+// WriteResult stamps r with the current schema version and writes it.
+func WriteResult(path string, r Result) error {
+	r.SchemaVersion = CurrentSchemaVersion
+	return WriteJSON(path, r)
+}
+
+// LoadResult reads a result file of any known schema version and migrates
+// it to the current one.
+func LoadResult(path string) (Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Result{}, err
+	}
+	r, err := DecodeResult(data)
+	if err != nil {
+		return Result{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// DecodeResult parses and migrates a result document.
+func DecodeResult(data []byte) (Result, error) {
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Result{}, fmt.Errorf("decode result: %w", err)
+	}
+	version := header.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+	if version > CurrentSchemaVersion {
+		return Result{}, fmt.Errorf("result schema version %d is newer than supported version %d", version, CurrentSchemaVersion)
+	}
+
+	var r Result
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Result{}, fmt.Errorf("decode result: %w", err)
+	}
+	if version < 2 {
+		migrateV1(&r)
+	}
+	r.SchemaVersion = CurrentSchemaVersion
+	return r, nil
+}
+
+// migrateV1 fills what version 1 files lack. They were only written by
+// runs that completed, so they are successes.
+func migrateV1(r *Result) {
+	if r.Status == "" {
+		r.Status = StatusSuccess
+	}
+}
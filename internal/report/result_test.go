@@ -0,0 +1,153 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/descheduler"
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/metrics"
+	"k8s-descheduler-benchmark/internal/promquery"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+var update = flag.Bool("update", false, "regenerate "+SchemaPath)
+
+const repoRoot = "../.."
+
+func TestResultSchemaUpToDate(t *testing.T) {
+	generated, err := ResultSchema()
+	if err != nil {
+		t.Fatalf("generate schema: %v", err)
+	}
+	path := filepath.Join(repoRoot, SchemaPath)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, generated, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	committed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v (run go test ./internal/report -run TestResultSchemaUpToDate -update)", path, err)
+	}
+	if !bytes.Equal(committed, generated) {
+		t.Fatalf("%s is stale; run go test ./internal/report -run TestResultSchemaUpToDate -update", SchemaPath)
+	}
+}
+
+func TestLoadResultMigratesV1(t *testing.T) {
+	for _, name := range []string{"baseline.json", "descheduler.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(repoRoot, "results", name)
+			result, err := LoadResult(path)
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if result.SchemaVersion != CurrentSchemaVersion || result.Status != StatusSuccess {
+				t.Fatalf("expected migrated success, got version %d status %q", result.SchemaVersion, result.Status)
+			}
+			if result.Config.RunID == "" || len(result.Samples) == 0 {
+				t.Fatalf("expected config and samples to survive migration")
+			}
+			validate(t, result)
+		})
+	}
+}
+
+func TestWriteResultRoundTrip(t *testing.T) {
+	now := time.Date(2026, 2, 9, 2, 50, 12, 0, time.UTC)
+	sample := metrics.Sample{Time: now, PodsStddev: 1.5, NodesCount: 3, PodsCounted: 60}
+	result := Result{
+		Status:      StatusFailed,
+		Error:       "pods not ready",
+		FailedPhase: "drain:done",
+		Diagnosis:   &Diagnosis{Ready: 50, Pending: 10, Reasons: map[string]int{"Unschedulable": 10}},
+		Config:      RunConfig{RunID: "run-a", Profile: "low-node-utilization", StartTime: now},
+		Phases:      []PhaseMarker{{Name: "workload:create", Time: now}},
+		Summary:     Summary{RunID: "run-a", Before: sample, After: sample},
+		Samples:     []metrics.Sample{sample},
+		BeforeSnapshot: metrics.Snapshot{Time: now, Nodes: map[string]metrics.NodeStats{
+			"w1": {Pods: 20, CPURequestedMilli: 2000},
+		}},
+		Evictions: []k8s.EvictionRecord{{PodName: "p", NodeName: "w1", EvictedAt: now}},
+		Activity: []descheduler.Activity{{
+			Iteration: 1,
+			Mode:      descheduler.ModeJob,
+			Start:     now,
+			End:       now,
+			Metrics:   &descheduler.Metrics{Time: now, PodsEvicted: []descheduler.EvictedCount{{Strategy: "LowNodeUtilization", Count: 3}}},
+			Decisions: &descheduler.Decisions{TotalEvicted: 3},
+		}},
+		Prometheus: &promquery.Enrichment{
+			URL:    "http://prometheus:9090",
+			Window: promquery.Window{Start: now, End: now, Step: "15s"},
+			Queries: []promquery.Result{{
+				Name:    "apiserver_pods_list_qps",
+				Expr:    "sum(rate(apiserver_request_total[30s]))",
+				Summary: &promquery.Summary{Avg: 1, Max: 2, P99: 2},
+				Series:  []promquery.Series{{Points: []promquery.Point{{Time: now, Value: 1}}}},
+			}},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "result.json")
+	if err := WriteResult(path, result); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	loaded, err := LoadResult(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.SchemaVersion != CurrentSchemaVersion || loaded.Status != StatusFailed || loaded.Diagnosis.Pending != 10 {
+		t.Fatalf("unexpected round trip: %+v", loaded)
+	}
+	validate(t, loaded)
+	validate(t, Result{Status: StatusSuccess, SchemaVersion: CurrentSchemaVersion})
+}
+
+func TestDecodeResultRejectsNewerVersion(t *testing.T) {
+	if _, err := DecodeResult([]byte(`{"schema_version": 99}`)); err == nil {
+		t.Fatalf("expected error for a newer schema version")
+	}
+}
+
+func validate(t *testing.T, result Result) {
+	t.Helper()
+	schemaFile, err := os.Open(filepath.Join(repoRoot, SchemaPath))
+	if err != nil {
+		t.Fatalf("open schema: %v", err)
+	}
+	defer schemaFile.Close()
+	doc, err := jsonschema.UnmarshalJSON(schemaFile)
+	if err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("result.schema.json", doc); err != nil {
+		t.Fatalf("add schema: %v", err)
+	}
+	schema, err := compiler.Compile("result.schema.json")
+	if err != nil {
+		t.Fatalf("compile schema: %v", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("encode result: %v", err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if err := schema.Validate(instance); err != nil {
+		t.Fatalf("result does not match %s: %v", SchemaPath, err)
+	}
+}
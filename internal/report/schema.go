@@ -0,0 +1,57 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+)
+
+// SchemaPath is where the generated result schema is kept, relative to the
+// repository root.
+const SchemaPath = "schema/result.schema.json"
+
+// ResultSchema generates the JSON Schema for Result.
+func ResultSchema() ([]byte, error) {
+	reflector := jsonschema.Reflector{
+		// Older writers and newer optional fields must not break validation.
+		AllowAdditionalProperties: true,
+		// promquery also has Result and Summary; qualify foreign types.
+		Namer: func(t reflect.Type) string {
+			if t.PkgPath() == "" || t.PkgPath() == reflect.TypeOf(Result{}).PkgPath() {
+				return t.Name()
+			}
+			return path.Base(t.PkgPath()) + "." + t.Name()
+		},
+	}
+	schema := reflector.Reflect(&Result{})
+	schema.Title = "deschedbench result"
+	schema.Description = fmt.Sprintf("Benchmark result file, schema_version %d.", CurrentSchemaVersion)
+	for _, def := range schema.Definitions {
+		allowNull(def)
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// allowNull lets slice and map properties of s be null, which is how
+// encoding/json writes nil slices and maps.
+func allowNull(s *jsonschema.Schema) {
+	if s.Properties == nil {
+		return
+	}
+	for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		prop := pair.Value
+		isMap := prop.Type == "object" && prop.Ref == "" && (prop.Properties == nil || prop.Properties.Len() == 0)
+		if prop.Type != "array" && !isMap {
+			continue
+		}
+		inner := *prop
+		*prop = jsonschema.Schema{AnyOf: []*jsonschema.Schema{&inner, {Type: "null"}}}
+	}
+}
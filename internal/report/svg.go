@@ -0,0 +1,327 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Chart geometry shared by every SVG chart in the HTML report.
+const (
+	chartWidth  = 860
+	chartHeight = 260
+	chartLeft   = 56
+	chartRight  = 16
+	chartTop    = 16
+	chartBottom = 36
+	chartPlotW  = chartWidth - chartLeft - chartRight
+)
+
+var chartPalette = []string{"#2f6fb0", "#d9822b", "#3a9a5b", "#b03a5b", "#7d5bb0", "#8a8a8a"}
+
+func color(i int) string {
+	return chartPalette[i%len(chartPalette)]
+}
+
+// svg accumulates SVG elements for one chart.
+type svg struct {
+	b strings.Builder
+}
+
+func newSVG(height int) *svg {
+	s := &svg{}
+	fmt.Fprintf(&s.b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" class="chart">`, chartWidth, height)
+	return s
+}
+
+func (s *svg) add(format string, args ...any) {
+	fmt.Fprintf(&s.b, format, args...)
+}
+
+func (s *svg) html() template.HTML {
+	return template.HTML(s.b.String() + "</svg>")
+}
+
+// timeAxis maps times onto the plot's x range.
+type timeAxis struct {
+	start, end time.Time
+}
+
+func newTimeAxis(times []time.Time) (timeAxis, bool) {
+	var axis timeAxis
+	for _, t := range times {
+		if t.IsZero() {
+			continue
+		}
+		if axis.start.IsZero() || t.Before(axis.start) {
+			axis.start = t
+		}
+		if t.After(axis.end) {
+			axis.end = t
+		}
+	}
+	if axis.start.IsZero() {
+		return axis, false
+	}
+	if !axis.end.After(axis.start) {
+		axis.end = axis.start.Add(time.Second)
+	}
+	return axis, true
+}
+
+func (a timeAxis) x(t time.Time) float64 {
+	span := a.end.Sub(a.start).Seconds()
+	return chartLeft + t.Sub(a.start).Seconds()/span*chartPlotW
+}
+
+func (a timeAxis) draw(s *svg, height int) {
+	bottom := float64(height - chartBottom)
+	span := a.end.Sub(a.start)
+	for i := 0; i <= 5; i++ {
+		t := a.start.Add(span * time.Duration(i) / 5)
+		x := a.x(t)
+		s.add(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="tick"/>`, x, bottom, x, bottom+4)
+		s.add(`<text x="%.1f" y="%.1f" text-anchor="middle" class="label">+%s</text>`, x, bottom+16, t.Sub(a.start).Round(time.Second))
+	}
+}
+
+// valueAxis maps values in [0, max] onto the plot's y range.
+type valueAxis struct {
+	max    float64
+	height int
+}
+
+func newValueAxis(max float64, height int) valueAxis {
+	if max <= 0 || math.IsNaN(max) || math.IsInf(max, 0) {
+		max = 1
+	}
+	return valueAxis{max: niceCeil(max), height: height}
+}
+
+func (a valueAxis) y(v float64) float64 {
+	plotH := float64(a.height - chartTop - chartBottom)
+	return float64(chartTop) + plotH - v/a.max*plotH
+}
+
+func (a valueAxis) draw(s *svg) {
+	for i := 0; i <= 4; i++ {
+		v := a.max * float64(i) / 4
+		y := a.y(v)
+		s.add(`<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" class="grid"/>`, chartLeft, y, chartWidth-chartRight, y)
+		s.add(`<text x="%d" y="%.1f" text-anchor="end" class="label">%s</text>`, chartLeft-6, y+4, formatTick(v))
+	}
+}
+
+// niceCeil rounds v up to 1, 2 or 5 times a power of ten.
+func niceCeil(v float64) float64 {
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*exp >= v {
+			return m * exp
+		}
+	}
+	return 10 * exp
+}
+
+func formatTick(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.2g", v)
+}
+
+// chartSeries is a named line for lineChart.
+type chartSeries struct {
+	Name   string
+	Times  []time.Time
+	Values []float64
+}
+
+// lineChart draws series over time with a vertical marker per phase.
+func lineChart(series []chartSeries, phases []PhaseMarker) template.HTML {
+	var times []time.Time
+	max := 0.0
+	for _, s := range series {
+		times = append(times, s.Times...)
+		for _, v := range s.Values {
+			max = math.Max(max, v)
+		}
+	}
+	for _, p := range phases {
+		times = append(times, p.Time)
+	}
+	xAxis, ok := newTimeAxis(times)
+	if !ok {
+		return emptyChart("no samples")
+	}
+	yAxis := newValueAxis(max, chartHeight)
+	s := newSVG(chartHeight)
+	yAxis.draw(s)
+	xAxis.draw(s, chartHeight)
+
+	for _, p := range phases {
+		x := xAxis.x(p.Time)
+		s.add(`<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" class="phase %s"><title>%s %s</title></line>`,
+			x, chartTop, x, chartHeight-chartBottom, phaseClass(p.Name), html.EscapeString(p.Name), p.Time.Format(time.RFC3339))
+		if isKeyPhase(p.Name) {
+			s.add(`<text x="%.1f" y="%d" class="phase-label" transform="rotate(-90 %.1f %d)">%s</text>`,
+				x+3, chartTop+4, x+3, chartTop+4, html.EscapeString(p.Name))
+		}
+	}
+	for i, line := range series {
+		var points []string
+		for j, t := range line.Times {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", xAxis.x(t), yAxis.y(line.Values[j])))
+		}
+		s.add(`<polyline points="%s" fill="none" stroke="%s" stroke-width="2"><title>%s</title></polyline>`,
+			strings.Join(points, " "), color(i), html.EscapeString(line.Name))
+	}
+	return s.html()
+}
+
+func isKeyPhase(name string) bool {
+	switch name {
+	case "cordon:start", "drain:done", "uncordon:done", "descheduler:run":
+		return true
+	}
+	return false
+}
+
+func phaseClass(name string) string {
+	if i := strings.Index(name, ":"); i > 0 {
+		return "phase-" + name[:i]
+	}
+	return "phase-other"
+}
+
+// barGroup is one category of a grouped bar chart.
+type barGroup struct {
+	Label  string
+	Values []float64
+}
+
+// barChart draws grouped bars; names label the bars within each group.
+func barChart(groups []barGroup, names []string) template.HTML {
+	if len(groups) == 0 {
+		return emptyChart("no data")
+	}
+	max := 0.0
+	for _, g := range groups {
+		for _, v := range g.Values {
+			max = math.Max(max, v)
+		}
+	}
+	yAxis := newValueAxis(max, chartHeight)
+	s := newSVG(chartHeight)
+	yAxis.draw(s)
+
+	groupW := float64(chartPlotW) / float64(len(groups))
+	barW := groupW * 0.8 / float64(len(names))
+	for gi, g := range groups {
+		x0 := float64(chartLeft) + groupW*float64(gi) + groupW*0.1
+		for bi, v := range g.Values {
+			x := x0 + barW*float64(bi)
+			y := yAxis.y(v)
+			s.add(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s %s: %s</title></rect>`,
+				x, y, barW-1, yAxis.y(0)-y, color(bi), html.EscapeString(g.Label), html.EscapeString(names[bi]), formatTick(v))
+		}
+		s.add(`<text x="%.1f" y="%d" text-anchor="middle" class="label">%s</text>`,
+			x0+groupW*0.4, chartHeight-chartBottom+16, html.EscapeString(g.Label))
+	}
+	for i, name := range names {
+		s.add(`<rect x="%d" y="%d" width="10" height="10" fill="%s"/><text x="%d" y="%d" class="label">%s</text>`,
+			chartWidth-chartRight-150+i*75, 2, color(i), chartWidth-chartRight-136+i*75, 11, html.EscapeString(name))
+	}
+	return s.html()
+}
+
+// timelineEvent is a point on a categorical timeline row.
+type timelineEvent struct {
+	Row   string
+	Time  time.Time
+	Label string
+}
+
+// timeline draws events as dots, one row per distinct Row value.
+func timeline(events []timelineEvent, phases []PhaseMarker) template.HTML {
+	if len(events) == 0 {
+		return emptyChart("no evictions")
+	}
+	rowIndex := map[string]int{}
+	var rows []string
+	var times []time.Time
+	for _, e := range events {
+		if _, ok := rowIndex[e.Row]; !ok {
+			rowIndex[e.Row] = len(rows)
+			rows = append(rows, e.Row)
+		}
+		times = append(times, e.Time)
+	}
+	for _, p := range phases {
+		times = append(times, p.Time)
+	}
+	sort.Strings(rows)
+	for i, r := range rows {
+		rowIndex[r] = i
+	}
+	xAxis, _ := newTimeAxis(times)
+	height := chartTop + chartBottom + 24*len(rows)
+	s := newSVG(height)
+	xAxis.draw(s, height)
+	for _, p := range phases {
+		if !isKeyPhase(p.Name) {
+			continue
+		}
+		x := xAxis.x(p.Time)
+		s.add(`<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" class="phase %s"><title>%s</title></line>`,
+			x, chartTop, x, height-chartBottom, phaseClass(p.Name), html.EscapeString(p.Name))
+	}
+	for i, r := range rows {
+		y := chartTop + 12 + 24*i
+		s.add(`<text x="%d" y="%d" text-anchor="end" class="label">%s</text>`, chartLeft-6, y+4, html.EscapeString(r))
+		s.add(`<line x1="%d" y1="%d" x2="%d" y2="%d" class="grid"/>`, chartLeft, y, chartWidth-chartRight, y)
+	}
+	for _, e := range events {
+		s.add(`<circle cx="%.1f" cy="%d" r="4" fill="%s"><title>%s</title></circle>`,
+			xAxis.x(e.Time), chartTop+12+24*rowIndex[e.Row], color(1), html.EscapeString(e.Label))
+	}
+	return s.html()
+}
+
+// histogram buckets values into bins of equal width and draws them.
+func histogram(values []float64, bins int, unit string) template.HTML {
+	if len(values) == 0 {
+		return emptyChart("no data")
+	}
+	max := 0.0
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	width := niceCeil(math.Max(max, 1)) / float64(bins)
+	counts := make([]float64, bins)
+	for _, v := range values {
+		i := int(v / width)
+		if i >= bins {
+			i = bins - 1
+		}
+		counts[i]++
+	}
+	groups := make([]barGroup, bins)
+	for i := range counts {
+		groups[i] = barGroup{
+			Label:  fmt.Sprintf("%s–%s%s", formatTick(width*float64(i)), formatTick(width*float64(i+1)), unit),
+			Values: []float64{counts[i]},
+		}
+	}
+	return barChart(groups, []string{"pods"})
+}
+
+func emptyChart(message string) template.HTML {
+	s := newSVG(60)
+	s.add(`<text x="%d" y="34" text-anchor="middle" class="label">%s</text>`, chartWidth/2, html.EscapeString(message))
+	return s.html()
+}
@@ -0,0 +1,91 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/benchmark"
+	"k8s-descheduler-benchmark/internal/grafana"
+	"k8s-descheduler-benchmark/internal/logging"
+)
+
+// stepAnnotator turns finished maintenance steps into Grafana region
+// annotations: one per maintenance window (cordon start to drain done) and
+// one per descheduler run. A nil stepAnnotator does nothing.
+type stepAnnotator struct {
+	client      *grafana.Client
+	tags        []string
+	logger      *slog.Logger
+	cordonStart time.Time
+}
+
+func newStepAnnotator(client *grafana.Client, runID, profile string, logger *slog.Logger) *stepAnnotator {
+	if client == nil {
+		return nil
+	}
+	return &stepAnnotator{
+		client: client,
+		tags:   []string{"deschedbench", "run_id:" + runID, "profile:" + profile},
+		logger: logger,
+	}
+}
+
+// Record posts the annotation for step, if it closes a region. Failures
+// are logged; annotations never fail the run.
+func (a *stepAnnotator) Record(step benchmark.StepRecord) {
+	if a == nil {
+		return
+	}
+	var annotation grafana.Annotation
+	switch step.Name {
+	case "cordon":
+		a.cordonStart = step.Start
+		if step.Err == nil {
+			return
+		}
+		annotation = a.region("maintenance", step.Start, step, fmt.Sprintf("cordon %s", step.Node))
+	case "drain":
+		start := a.cordonStart
+		if start.IsZero() {
+			start = step.Start
+		}
+		a.cordonStart = time.Time{}
+		annotation = a.region("maintenance", start, step, fmt.Sprintf("cordon → drain done on %s", step.Node))
+	case "descheduler":
+		annotation = a.region("descheduler", step.Start, step, "descheduler run")
+	default:
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := a.client.Create(ctx, annotation); err != nil {
+		a.logger.Warn("grafana annotation failed",
+			logging.StringField("step", step.Name),
+			logging.ErrorField(err),
+		)
+	}
+}
+
+func (a *stepAnnotator) region(kind string, start time.Time, step benchmark.StepRecord, text string) grafana.Annotation {
+	tags := append([]string{}, a.tags...)
+	tags = append(tags, "step:"+kind)
+	if step.Iteration > 0 {
+		tags = append(tags, fmt.Sprintf("iteration:%d", step.Iteration))
+		text = fmt.Sprintf("%s (iteration %d)", text, step.Iteration)
+	}
+	if step.Node != "" {
+		tags = append(tags, "node:"+step.Node)
+	}
+	if step.Err != nil {
+		text = fmt.Sprintf("%s failed: %v", text, step.Err)
+	}
+	return grafana.Annotation{
+		Time:    start,
+		TimeEnd: step.End,
+		Tags:    tags,
+		Text:    text,
+	}
+}
@@ -0,0 +1,73 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/benchmark"
+	"k8s-descheduler-benchmark/internal/grafana"
+	"k8s-descheduler-benchmark/internal/logging"
+)
+
+func TestStepAnnotatorRegions(t *testing.T) {
+	type posted struct {
+		Time    int64    `json:"time"`
+		TimeEnd int64    `json:"timeEnd"`
+		Tags    []string `json:"tags"`
+		Text    string   `json:"text"`
+	}
+	var (
+		mu   sync.Mutex
+		got  []posted
+		base = time.UnixMilli(1_700_000_000_000)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p posted
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		got = append(got, p)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	annotator := newStepAnnotator(grafana.NewClient(server.URL, ""), "run-a", "baseline", logging.GetLogger())
+	at := func(s int) time.Time { return base.Add(time.Duration(s) * time.Second) }
+	for _, step := range []benchmark.StepRecord{
+		{Name: "workload", Start: at(0), End: at(5)},
+		{Name: "cordon", Iteration: 1, Node: "w1", Start: at(10), End: at(11)},
+		{Name: "drain", Iteration: 1, Node: "w1", Start: at(11), End: at(40)},
+		{Name: "reschedule", Iteration: 1, Node: "w1", Start: at(40), End: at(50)},
+		{Name: "descheduler", Iteration: 1, Node: "w1", Start: at(55), End: at(70)},
+	} {
+		annotator.Record(step)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected maintenance and descheduler regions, got %d", len(got))
+	}
+	if got[0].Time != at(10).UnixMilli() || got[0].TimeEnd != at(40).UnixMilli() {
+		t.Fatalf("expected cordon start to drain done, got %d-%d", got[0].Time, got[0].TimeEnd)
+	}
+	wantTags := []string{"deschedbench", "run_id:run-a", "profile:baseline", "step:maintenance", "iteration:1", "node:w1"}
+	if len(got[0].Tags) != len(wantTags) {
+		t.Fatalf("unexpected tags: %v", got[0].Tags)
+	}
+	for i, tag := range wantTags {
+		if got[0].Tags[i] != tag {
+			t.Fatalf("unexpected tags: %v", got[0].Tags)
+		}
+	}
+	if got[1].Time != at(55).UnixMilli() || got[1].Text != "descheduler run (iteration 1)" {
+		t.Fatalf("unexpected descheduler region: %+v", got[1])
+	}
+}
+
+func TestStepAnnotatorNil(t *testing.T) {
+	annotator := newStepAnnotator(nil, "run-a", "baseline", logging.GetLogger())
+	annotator.Record(benchmark.StepRecord{Name: "drain"})
+}
@@ -0,0 +1,129 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"k8s-descheduler-benchmark/internal/descheduler"
+	"k8s-descheduler-benchmark/internal/logging"
+	"k8s-descheduler-benchmark/internal/report"
+)
+
+// RunImages runs the same scenario and policy once per descheduler image and
+// writes a version-comparison summary next to the per-image result files.
+func (r *Runner) RunImages(ctx context.Context, cfg RunConfig, images []string) error {
+	if len(images) == 0 {
+		return fmt.Errorf("at least one descheduler image is required")
+	}
+	if len(images) == 1 {
+		cfg.DeschedulerImage = images[0]
+		return r.Run(ctx, cfg)
+	}
+	if cfg.Profile == descheduler.ProfileBaseline {
+		return fmt.Errorf("comparing descheduler images requires a descheduler profile, not %q", cfg.Profile)
+	}
+	logger := r.Logger
+	if logger == nil {
+		logger = logging.GetLogger()
+	}
+
+	// Every profile shares the default result path, so comparisons name
+	// theirs after the profile to keep side by side.
+	basePath := cfg.OutputPath
+	if basePath == "" {
+		basePath = withSuffix(defaultOutputPath(cfg.Profile), cfg.Profile)
+	}
+
+	runs := make([]report.ImageRun, 0, len(images))
+	var runErr, assertionErr error
+	refs := imageRefs(images)
+	for i, image := range images {
+		runCfg := cfg
+		runCfg.DeschedulerImage = image
+		runCfg.OutputPath = withSuffix(basePath, refs[i])
+		if cfg.JUnitPath != "" {
+			runCfg.JUnitPath = withSuffix(cfg.JUnitPath, refs[i])
+		}
+		logger.Info("image comparison run", logging.StringField("image", image))
+
+		outcome, err := r.run(ctx, runCfg)
+		run := report.ImageRun{
+			Image:      image,
+			ImageID:    outcome.ImageID,
+			OutputPath: runCfg.OutputPath,
+			Summary:    outcome.Summary,
+			Evictions:  outcome.Evictions,
+		}
+		switch {
+		case errors.Is(err, context.Canceled):
+			return err
+		case errors.Is(err, ErrAssertionsFailed):
+			// The run itself succeeded and still belongs in the comparison.
+			assertionErr = fmt.Errorf("image %s: %w", image, err)
+		case err != nil:
+			logger.Error("image comparison run failed", logging.StringField("image", image), logging.ErrorField(err))
+			run.Error = err.Error()
+			if runErr == nil {
+				runErr = fmt.Errorf("image %s: %w", image, err)
+			}
+		}
+		runs = append(runs, run)
+	}
+
+	comparison := report.CompareImages(scenarioName, cfg.Profile, runs)
+	comparisonPath := withSuffix(basePath, "comparison")
+	if err := report.WriteJSON(comparisonPath, comparison); err != nil {
+		return err
+	}
+	logImageComparison(comparison)
+	logger.Info("image comparison output", logging.StringField("path", comparisonPath))
+	// A failed run fails the comparison; its error is also in the comparison.
+	if runErr != nil {
+		return runErr
+	}
+	return assertionErr
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// imageRefs names each image for its result files: the tag (or digest), or
+// the whole reference when another image has the same tag.
+func imageRefs(images []string) []string {
+	refs := make([]string, len(images))
+	seen := map[string]int{}
+	for i, image := range images {
+		refs[i] = fileSafe(imageTag(image))
+		seen[refs[i]]++
+	}
+	for i, image := range images {
+		if seen[refs[i]] > 1 {
+			refs[i] = fileSafe(image)
+		}
+	}
+	return refs
+}
+
+func imageTag(image string) string {
+	if idx := strings.LastIndex(image, "@"); idx >= 0 {
+		return image[idx+1:]
+	}
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		return image[idx+1:]
+	}
+	return filepath.Base(image)
+}
+
+func fileSafe(s string) string {
+	return strings.Trim(unsafeFileChars.ReplaceAllString(s, "-"), "-")
+}
+
+// withSuffix appends "-<suffix>" to the file name of path, before its
+// extension.
+func withSuffix(path, suffix string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + suffix + ext
+}
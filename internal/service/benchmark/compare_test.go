@@ -0,0 +1,30 @@
+package benchmark
+
+import "testing"
+
+func TestImageRefs(t *testing.T) {
+	cases := map[string]string{
+		"registry.k8s.io/descheduler/descheduler:v0.32.2":    "v0.32.2",
+		"localhost:5000/descheduler":                         "descheduler",
+		"registry.k8s.io/descheduler/descheduler@sha256:abc": "sha256-abc",
+	}
+	for image, want := range cases {
+		if got := imageRefs([]string{image})[0]; got != want {
+			t.Fatalf("imageRefs(%q) = %q, want %q", image, got, want)
+		}
+	}
+	refs := imageRefs([]string{"a/descheduler:v0.32.2", "b/descheduler:v0.32.2", "a/descheduler:v0.31.0"})
+	if refs[0] != "a-descheduler-v0.32.2" || refs[1] != "b-descheduler-v0.32.2" || refs[2] != "v0.31.0" {
+		t.Fatalf("unexpected refs for a shared tag: %v", refs)
+	}
+}
+
+func TestWithSuffix(t *testing.T) {
+	if got := withSuffix("results/descheduler.json", "v0.32.2"); got != "results/descheduler-v0.32.2.json" {
+		t.Fatalf("unexpected path %q", got)
+	}
+	base := withSuffix(defaultOutputPath("taints"), "taints")
+	if got := withSuffix(base, "comparison"); got != "results/descheduler-taints-comparison.json" {
+		t.Fatalf("unexpected comparison path %q", got)
+	}
+}
@@ -0,0 +1,253 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/capacity"
+	"k8s-descheduler-benchmark/internal/descheduler"
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/workloads"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DryRunReport is everything a run would do, computed from the plan and the
+// current cluster state without mutating anything.
+type DryRunReport struct {
+	RunID                string            `json:"run_id"`
+	Namespace            string            `json:"namespace"`
+	OutputPath           string            `json:"output_path"`
+	Profile              string            `json:"profile"`
+	Labels               map[string]string `json:"labels"`
+	PriorityClasses      []string          `json:"priority_classes,omitempty"`
+	Deployments          []string          `json:"deployments"`
+	DeschedulerImage     string            `json:"descheduler_image,omitempty"`
+	DeschedulerMode      string            `json:"descheduler_mode,omitempty"`
+	DeschedulerManifests []string          `json:"descheduler_manifests,omitempty"`
+	Policy               string            `json:"policy,omitempty"`
+	DrainOrder           [][]string        `json:"drain_order"`
+	Capacity             capacity.Report   `json:"capacity"`
+	MinDuration          string            `json:"min_duration"`
+	MaxDuration          string            `json:"max_duration"`
+	NoiseNamespaces      []string          `json:"noise_namespaces,omitempty"`
+	// NoiseUnplaced counts noise pods that would not fit next to what
+	// already runs; the run would fail waiting for them.
+	NoiseUnplaced int32 `json:"noise_unplaced,omitempty"`
+}
+
+// DryRun builds the plan for cfg and reads nodes and pods to predict the
+// drain order and capacity. Nothing is created, cordoned or deleted.
+func (r *Runner) DryRun(ctx context.Context, cfg RunConfig) (DryRunReport, error) {
+	if r.Client == nil {
+		return DryRunReport{}, fmt.Errorf("client is required")
+	}
+	plan, err := NewPlanBuilder().Build(cfg)
+	if err != nil {
+		return DryRunReport{}, err
+	}
+	out := DryRunReport{
+		RunID:      plan.RunID,
+		Namespace:  plan.Namespace,
+		OutputPath: plan.OutputPath,
+		Profile:    cfg.Profile,
+		Labels:     plan.Labels,
+	}
+
+	out.PriorityClasses, err = renderPriorityClasses(plan)
+	if err != nil {
+		return DryRunReport{}, err
+	}
+	out.Deployments, err = renderDeployments(workloads.WorkloadConfig{
+		Namespace:       plan.Namespace,
+		NamePrefix:      "deschedbench",
+		Labels:          plan.Labels,
+		Annotations:     map[string]string{k8s.RunIDAnnotation: plan.RunID},
+		Mix:             plan.Mix,
+		SizeClasses:     plan.SizeClasses,
+		PodImage:        workloadImage,
+		PodLabels:       plan.Labels,
+		PriorityClasses: plan.PriorityClasses,
+	})
+	if err != nil {
+		return DryRunReport{}, err
+	}
+	for _, noise := range plan.Noise {
+		manifests, err := renderDeployments(noise)
+		if err != nil {
+			return DryRunReport{}, err
+		}
+		out.NoiseNamespaces = append(out.NoiseNamespaces, noise.Namespace)
+		out.Deployments = append(out.Deployments, manifests...)
+	}
+
+	if plan.PolicyYAML != "" {
+		out.DeschedulerImage = plan.DeschedulerImage
+		out.DeschedulerMode = plan.DeschedulerMode
+		out.Policy = plan.PolicyYAML
+		out.DeschedulerManifests, err = descheduler.RenderManifests(descheduler.Config{
+			Namespace:    plan.Namespace,
+			Image:        plan.DeschedulerImage,
+			PolicyYAML:   plan.PolicyYAML,
+			Mode:         plan.DeschedulerMode,
+			CronSchedule: plan.DeschedulerCron,
+			Interval:     plan.DeschedulerInterval,
+			JobName:      dryRunJobName(plan),
+			RunID:        plan.RunID,
+		})
+		if err != nil {
+			return DryRunReport{}, err
+		}
+	}
+
+	shapes, err := capacity.Shapes(plan.Mix, plan.SizeClasses)
+	if err != nil {
+		return DryRunReport{}, err
+	}
+	nodes, err := k8s.ListNodes(ctx, r.Client, "")
+	if err != nil {
+		return DryRunReport{}, err
+	}
+	pods, err := k8s.ListPods(ctx, r.Client, "", "")
+	if err != nil {
+		return DryRunReport{}, err
+	}
+	free := capacity.FreeNodes(nodes, pods, plan.Namespace)
+	// Noise is created before the drain plan is checked, so it takes its
+	// share of the workers first.
+	free, out.NoiseUnplaced, err = capacity.ReserveNoise(free, plan.Noise)
+	if err != nil {
+		return DryRunReport{}, err
+	}
+	out.DrainOrder = capacity.DrainOrder(free, defaultDrainIterations, 1)
+	out.Capacity = capacity.PlanDrains(free, out.DrainOrder, shapes)
+
+	minDuration, maxDuration := estimateDuration(plan)
+	out.MinDuration = minDuration.String()
+	out.MaxDuration = maxDuration.String()
+	return out, nil
+}
+
+// renderDeployments renders the workload objects of cfg as YAML documents.
+func renderDeployments(cfg workloads.WorkloadConfig) ([]string, error) {
+	objects, err := workloads.BuildObjects(cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, string(data))
+	}
+	return out, nil
+}
+
+// renderPriorityClasses renders the PriorityClasses of the plan's tiers as
+// YAML documents, in tier order.
+func renderPriorityClasses(plan Plan) ([]string, error) {
+	values := workloads.DefaultPriorityTiers()
+	var out []string
+	for _, tier := range workloads.MixTiers(plan.Mix) {
+		pc := k8s.BuildPriorityClass(plan.PriorityClasses[tier], values[tier], plan.RunID)
+		pc.APIVersion = "scheduling.k8s.io/v1"
+		pc.Kind = "PriorityClass"
+		data, err := yaml.Marshal(pc)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, string(data))
+	}
+	return out, nil
+}
+
+// dryRunJobName mirrors the Job name of the first iteration in job mode and
+// the long-running workload name otherwise.
+func dryRunJobName(plan Plan) string {
+	if plan.DeschedulerMode == descheduler.ModeJob {
+		return fmt.Sprintf("deschedbench-descheduler-%s-%d", plan.RunID, 1)
+	}
+	return ""
+}
+
+// estimateDuration bounds the run time. The lower bound is the fixed waits;
+// the upper bound lets every wait run into its timeout.
+func estimateDuration(plan Plan) (time.Duration, time.Duration) {
+	iterations := time.Duration(defaultDrainIterations)
+	minDuration := iterations * defaultPostUncordonWait
+	// Noise and workload readiness, then per iteration drain, reschedule
+	// and the descheduler Job.
+	waits := time.Duration(len(plan.Noise)) + 1 + 2*iterations
+	if plan.PolicyYAML != "" && plan.DeschedulerMode == descheduler.ModeJob {
+		waits += iterations
+	}
+	maxDuration := minDuration + waits*defaultWaitTimeout
+	return minDuration, maxDuration
+}
+
+// WriteDryRun renders a dry-run report for review.
+func WriteDryRun(w io.Writer, report DryRunReport) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "run id:     %s\n", report.RunID)
+	fmt.Fprintf(&b, "namespace:  %s\n", report.Namespace)
+	fmt.Fprintf(&b, "output:     %s\n", report.OutputPath)
+	fmt.Fprintf(&b, "profile:    %s\n", report.Profile)
+	fmt.Fprintf(&b, "labels:     %s\n", formatLabels(report.Labels))
+	fmt.Fprintf(&b, "duration:   %s to %s\n", report.MinDuration, report.MaxDuration)
+	if len(report.NoiseNamespaces) > 0 {
+		fmt.Fprintf(&b, "noise:      %s (%d pods unplaced)\n", strings.Join(report.NoiseNamespaces, ","), report.NoiseUnplaced)
+	}
+
+	b.WriteString("\ndrain order:\n")
+	for i, nodes := range report.DrainOrder {
+		fmt.Fprintf(&b, "  iteration %d: %s\n", i+1, strings.Join(nodes, ","))
+	}
+	b.WriteString("\ncapacity:\n")
+	for _, line := range strings.Split(capacity.Format(report.Capacity), "\n") {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+
+	if len(report.PriorityClasses) > 0 {
+		b.WriteString("\npriority classes:\n")
+		for _, manifest := range report.PriorityClasses {
+			b.WriteString("---\n")
+			b.WriteString(manifest)
+		}
+	}
+	b.WriteString("\nworkloads:\n")
+	for _, manifest := range report.Deployments {
+		b.WriteString("---\n")
+		b.WriteString(manifest)
+	}
+	if report.Policy == "" {
+		b.WriteString("\ndescheduler: none (baseline)\n")
+	} else {
+		fmt.Fprintf(&b, "\ndescheduler: %s (%s mode)\n", report.DeschedulerImage, report.DeschedulerMode)
+		for _, manifest := range report.DeschedulerManifests {
+			b.WriteString("---\n")
+			b.WriteString(strings.TrimRight(manifest, "\n"))
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+labels[k])
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,149 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDryRunDoesNotMutateCluster(t *testing.T) {
+	// Policies are resolved relative to the repository root.
+	t.Chdir("../../..")
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			}},
+		}
+	}
+	client := fake.NewSimpleClientset(node("w1"), node("w2"), node("w3"))
+	runner := Runner{Client: client}
+	report, err := runner.DryRun(context.Background(), RunConfig{
+		PodsTotal:       10,
+		PodCPU:          "100m",
+		PodMemory:       "128Mi",
+		Profile:         "low-node-utilization",
+		DeschedulerMode: "job",
+	})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	for _, action := range client.Actions() {
+		if verb := action.GetVerb(); verb != "list" && verb != "get" {
+			t.Fatalf("dry run issued %s %s", verb, action.GetResource().Resource)
+		}
+	}
+	if len(report.DrainOrder) != defaultDrainIterations || report.DrainOrder[0][0] != "w1" || report.DrainOrder[1][0] != "w2" {
+		t.Fatalf("unexpected drain order: %v", report.DrainOrder)
+	}
+	if !report.Capacity.Feasible {
+		t.Fatalf("expected feasible capacity, got %+v", report.Capacity)
+	}
+	if len(report.Deployments) != 1 || !strings.Contains(report.Deployments[0], "replicas: 10") {
+		t.Fatalf("unexpected deployments: %v", report.Deployments)
+	}
+	if len(report.DeschedulerManifests) == 0 || strings.Contains(strings.Join(report.DeschedulerManifests, ""), "{{") {
+		t.Fatalf("expected fully rendered descheduler manifests")
+	}
+
+	var out bytes.Buffer
+	if err := WriteDryRun(&out, report); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "iteration 2: w2") {
+		t.Fatalf("expected drain order in output:\n%s", out.String())
+	}
+}
+
+func TestDryRunListsPriorityClassesSeparately(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "w1"},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+			corev1.ResourcePods:   resource.MustParse("110"),
+		}},
+	}
+	runner := Runner{Client: fake.NewSimpleClientset(node)}
+	report, err := runner.DryRun(context.Background(), RunConfig{
+		PodCPU:    "100m",
+		PodMemory: "128Mi",
+		Profile:   "baseline",
+		Mix:       "small@low=4,small@critical=2",
+	})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(report.PriorityClasses) != 2 {
+		t.Fatalf("expected a PriorityClass per tier, got %v", report.PriorityClasses)
+	}
+	for _, manifest := range report.PriorityClasses {
+		if !strings.Contains(manifest, "kind: PriorityClass") {
+			t.Fatalf("unexpected priority class manifest:\n%s", manifest)
+		}
+	}
+	for _, manifest := range report.Deployments {
+		if strings.Contains(manifest, "kind: PriorityClass") {
+			t.Fatalf("expected no PriorityClass among deployments:\n%s", manifest)
+		}
+	}
+}
+
+func TestDryRunReservesNoise(t *testing.T) {
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			}},
+		}
+	}
+	client := fake.NewSimpleClientset(node("w1"), node("w2"), node("w3"))
+	runner := Runner{Client: client}
+	cfg := RunConfig{
+		PodsTotal:       10,
+		PodCPU:          "100m",
+		PodMemory:       "128Mi",
+		Profile:         "baseline",
+		NoiseNamespaces: 2,
+		NoiseMix:        "large=4",
+	}
+	report, err := runner.DryRun(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(report.NoiseNamespaces) != 2 || len(report.Deployments) != 3 || report.NoiseUnplaced != 0 {
+		t.Fatalf("unexpected noise in report: %v, %d deployments, %d unplaced", report.NoiseNamespaces, len(report.Deployments), report.NoiseUnplaced)
+	}
+	if !report.Capacity.Feasible {
+		t.Fatalf("expected feasible capacity next to noise, got %+v", report.Capacity)
+	}
+
+	// 24 large pods ask for 12 CPUs on 6.
+	cfg.NoiseMix = "large=12"
+	report, err = runner.DryRun(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if report.NoiseUnplaced != 12 || report.Capacity.Feasible {
+		t.Fatalf("expected noise to crowd out the benchmark, got %d unplaced, %+v", report.NoiseUnplaced, report.Capacity)
+	}
+	var out bytes.Buffer
+	if err := WriteDryRun(&out, report); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "(12 pods unplaced)") {
+		t.Fatalf("expected noise line in output:\n%s", out.String())
+	}
+}
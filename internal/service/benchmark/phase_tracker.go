@@ -24,6 +24,11 @@ type PhaseRecorder struct {
 	afterSnap    metrics.Snapshot
 	beforeSample metrics.Sample
 	afterSample  metrics.Sample
+
+	// clusterBefore and clusterAfter count every namespace, so balance can
+	// also be judged with other tenants' pods on the nodes.
+	clusterBefore metrics.Snapshot
+	clusterAfter  metrics.Snapshot
 }
 
 func NewPhaseRecorder(client kubernetes.Interface, opts metrics.SnapshotOptions, logger *slog.Logger) *PhaseRecorder {
@@ -53,14 +58,24 @@ func (p *PhaseRecorder) Record(ctx context.Context, name string) error {
 		return err
 	}
 	sample := metrics.DeriveSample(snap)
+	cluster := snap
+	if p.opts.NamespaceOnly {
+		clusterOpts := p.opts
+		clusterOpts.NamespaceOnly = false
+		if cluster, err = metrics.CollectSnapshot(ctx, p.client, clusterOpts); err != nil {
+			return err
+		}
+	}
 
 	p.mu.Lock()
 	if name == "snapshot:before" {
 		p.beforeSnap = snap
 		p.beforeSample = sample
+		p.clusterBefore = cluster
 	} else {
 		p.afterSnap = snap
 		p.afterSample = sample
+		p.clusterAfter = cluster
 	}
 	p.mu.Unlock()
 
@@ -92,6 +107,14 @@ func (p *PhaseRecorder) After() (metrics.Snapshot, metrics.Sample) {
 	return p.afterSnap, p.afterSample
 }
 
+// Cluster returns the cluster-wide snapshots of the before and after
+// phases.
+func (p *PhaseRecorder) Cluster() (metrics.Snapshot, metrics.Snapshot) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.clusterBefore, p.clusterAfter
+}
+
 func snapshotDoneMessage(name string) string {
 	switch name {
 	case "snapshot:before":
@@ -1,6 +1,15 @@
 package benchmark
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"k8s-descheduler-benchmark/internal/metrics"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
 
 func TestSnapshotDoneMessage(t *testing.T) {
 	if got := snapshotDoneMessage("snapshot:before"); got != "snapshot before done" {
@@ -13,3 +22,31 @@ func TestSnapshotDoneMessage(t *testing.T) {
 		t.Fatalf("expected replacement, got %s", got)
 	}
 }
+
+func TestPhaseRecorderClusterSnapshot(t *testing.T) {
+	pod := func(namespace, name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       corev1.PodSpec{NodeName: "n1"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1"}},
+		pod("bench", "a"),
+		pod("bench-noise-1", "b"),
+		pod("kube-system", "c"),
+	)
+	rec := NewPhaseRecorder(client, metrics.SnapshotOptions{Namespace: "bench", NamespaceOnly: true}, nil)
+	if err := rec.Record(context.Background(), "snapshot:before"); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+	before, _ := rec.Before()
+	clusterBefore, _ := rec.Cluster()
+	if before.TotalPodsCounted != 1 {
+		t.Fatalf("expected 1 benchmark pod, got %d", before.TotalPodsCounted)
+	}
+	if clusterBefore.TotalPodsCounted != 3 || clusterBefore.NamespaceOnly {
+		t.Fatalf("expected 3 pods cluster-wide, got %+v", clusterBefore)
+	}
+}
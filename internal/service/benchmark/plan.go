@@ -8,18 +8,28 @@ import (
 	"time"
 
 	"k8s-descheduler-benchmark/internal/descheduler"
+	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/workloads"
 )
 
 type Plan struct {
-	RunID         string
-	Namespace     string
-	OutputPath    string
-	Labels        map[string]string
-	LabelSelector string
-	Mix           workloads.Mix
-	SizeClasses   map[string]workloads.SizeClass
-	PolicyYAML    string
+	RunID               string
+	Namespace           string
+	OutputPath          string
+	Labels              map[string]string
+	LabelSelector       string
+	Mix                 workloads.Mix
+	SizeClasses         map[string]workloads.SizeClass
+	PolicyYAML          string
+	DeschedulerImage    string
+	DeschedulerMode     string
+	DeschedulerCron     string
+	DeschedulerInterval time.Duration
+	// Noise holds one workload per noise namespace; empty without noise.
+	Noise []workloads.WorkloadConfig
+	// PriorityClasses maps each priority tier of Mix to the PriorityClass
+	// created for this run; empty when the mix has no tiers.
+	PriorityClasses map[string]string
 }
 
 type PlanBuilder struct {
@@ -31,9 +41,36 @@ func NewPlanBuilder() *PlanBuilder {
 }
 
 func (b *PlanBuilder) Build(cfg RunConfig) (Plan, error) {
-	if cfg.PodsTotal <= 0 {
-		return Plan{}, fmt.Errorf("--pods must be > 0")
+	mix, sizeClasses, err := benchmarkMix(cfg)
+	if err != nil {
+		return Plan{}, err
+	}
+	image := cfg.DeschedulerImage
+	if image == "" {
+		image = deschedulerImagePinned
+	}
+	mode := cfg.DeschedulerMode
+	if mode == "" {
+		mode = descheduler.ModeJob
+	}
+	if err := descheduler.ValidateMode(mode); err != nil {
+		return Plan{}, err
+	}
+	cron := ""
+	if mode == descheduler.ModeCronJob {
+		cron = cfg.DeschedulerCron
+		if cron == "" {
+			cron = defaultDeschedulerCron
+		}
 	}
+	var interval time.Duration
+	if mode == descheduler.ModeDeployment {
+		interval = cfg.DeschedulerInterval
+		if interval <= 0 {
+			interval = defaultDeschedulerPeriod
+		}
+	}
+
 	now := b.Now
 	if now == nil {
 		now = time.Now
@@ -47,37 +84,119 @@ func (b *PlanBuilder) Build(cfg RunConfig) (Plan, error) {
 		outPath = defaultOutputPath(cfg.Profile)
 	}
 
-	mix := workloads.Mix{"small": cfg.PodsTotal}
-	sizeClasses := map[string]workloads.SizeClass{
-		"small": {Name: "small", CPU: cfg.PodCPU, Memory: cfg.PodMemory},
-	}
-
 	labels := map[string]string{
 		"deschedbench":     "true",
 		"deschedbench-run": runID,
 	}
 
+	noise, err := noiseWorkloads(cfg, namespace, runID)
+	if err != nil {
+		return Plan{}, err
+	}
+
 	policyYAML := ""
 	if cfg.Profile != descheduler.ProfileBaseline {
-		var err error
 		policyYAML, err = loadPolicy(cfg.Profile, namespace)
 		if err != nil {
 			return Plan{}, err
 		}
+		if cfg.PriorityThreshold > 0 {
+			if policyYAML, err = descheduler.WithPriorityThreshold(policyYAML, cfg.PriorityThreshold); err != nil {
+				return Plan{}, fmt.Errorf("--priority-threshold: %w", err)
+			}
+		}
+	}
+	var priorityClasses map[string]string
+	for _, tier := range workloads.MixTiers(mix) {
+		if priorityClasses == nil {
+			priorityClasses = map[string]string{}
+		}
+		priorityClasses[tier] = k8s.PriorityClassName(runID, tier)
 	}
 
 	return Plan{
-		RunID:         runID,
-		Namespace:     namespace,
-		OutputPath:    outPath,
-		Labels:        labels,
-		LabelSelector: labelsToSelector(labels),
-		Mix:           mix,
-		SizeClasses:   sizeClasses,
-		PolicyYAML:    policyYAML,
+		RunID:               runID,
+		Namespace:           namespace,
+		OutputPath:          outPath,
+		Labels:              labels,
+		LabelSelector:       labelsToSelector(labels),
+		Mix:                 mix,
+		SizeClasses:         sizeClasses,
+		PolicyYAML:          policyYAML,
+		DeschedulerImage:    image,
+		DeschedulerMode:     mode,
+		DeschedulerCron:     cron,
+		DeschedulerInterval: interval,
+		Noise:               noise,
+		PriorityClasses:     priorityClasses,
 	}, nil
 }
 
+// benchmarkMix returns the benchmark workload mix: --mix when set, with the
+// default size classes and small sized by --cpu and --mem, otherwise
+// --pods small Deployment pods.
+func benchmarkMix(cfg RunConfig) (workloads.Mix, map[string]workloads.SizeClass, error) {
+	sizeClasses := map[string]workloads.SizeClass{
+		"small": {Name: "small", CPU: cfg.PodCPU, Memory: cfg.PodMemory},
+	}
+	if cfg.Mix == "" {
+		if cfg.PodsTotal <= 0 {
+			return nil, nil, fmt.Errorf("--pods must be > 0")
+		}
+		return workloads.Mix{"small": cfg.PodsTotal}, sizeClasses, nil
+	}
+	mix, err := workloads.ParseMix(cfg.Mix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("--mix: %w", err)
+	}
+	if workloads.MixTotal(mix) == 0 {
+		return nil, nil, fmt.Errorf("--mix must have at least one pod")
+	}
+	for name, size := range workloads.DefaultSizeClasses() {
+		if name != "small" {
+			sizeClasses[name] = size
+		}
+	}
+	return mix, sizeClasses, nil
+}
+
+// noiseWorkloads builds the background Deployments of each noise namespace.
+// They lack the deschedbench=true label the policies select on, so only
+// their requests take part in the benchmark.
+func noiseWorkloads(cfg RunConfig, namespace, runID string) ([]workloads.WorkloadConfig, error) {
+	if cfg.NoiseNamespaces < 0 {
+		return nil, fmt.Errorf("--noise-namespaces must be >= 0")
+	}
+	if cfg.NoiseNamespaces == 0 {
+		return nil, nil
+	}
+	mix, err := workloads.ParseMix(cfg.NoiseMix)
+	if err != nil {
+		return nil, fmt.Errorf("--noise-mix: %w", err)
+	}
+	if workloads.MixTotal(mix) == 0 {
+		return nil, fmt.Errorf("--noise-mix must have at least one pod")
+	}
+	labels := map[string]string{
+		"deschedbench-noise": "true",
+		"deschedbench-run":   runID,
+	}
+	out := make([]workloads.WorkloadConfig, 0, cfg.NoiseNamespaces)
+	for i := 1; i <= cfg.NoiseNamespaces; i++ {
+		out = append(out, workloads.WorkloadConfig{
+			Namespace:   fmt.Sprintf("%s-noise-%d", namespace, i),
+			NamePrefix:  "noise",
+			Labels:      labels,
+			Annotations: map[string]string{k8s.RunIDAnnotation: runID},
+			Mix:         mix,
+			SizeClasses: workloads.DefaultSizeClasses(),
+			PodImage:    workloadImage,
+			PodLabels:   labels,
+		})
+	}
+	return out, nil
+}
+
 func labelsToSelector(labels map[string]string) string {
 	parts := make([]string, 0, len(labels))
 	for k, v := range labels {
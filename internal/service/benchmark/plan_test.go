@@ -47,3 +47,103 @@ func TestPlanBuilderDefaults(t *testing.T) {
 		t.Fatalf("unexpected namespace: %s", plan.Namespace)
 	}
 }
+
+func TestPlanBuilderDeschedulerMode(t *testing.T) {
+	builder := NewPlanBuilder()
+	plan, err := builder.Build(RunConfig{PodsTotal: 10, Profile: "baseline", DeschedulerMode: "deployment"})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if plan.DeschedulerInterval != defaultDeschedulerPeriod {
+		t.Fatalf("expected default interval, got %s", plan.DeschedulerInterval)
+	}
+	if plan.DeschedulerCron != "" {
+		t.Fatalf("expected no cron schedule in deployment mode, got %q", plan.DeschedulerCron)
+	}
+	if _, err := builder.Build(RunConfig{PodsTotal: 10, Profile: "baseline", DeschedulerMode: "daemonset"}); err == nil {
+		t.Fatalf("expected error for unknown mode")
+	}
+}
+
+func TestPlanBuilderNoise(t *testing.T) {
+	builder := &PlanBuilder{
+		Now: func() time.Time {
+			return time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+		},
+	}
+	plan, err := builder.Build(RunConfig{PodsTotal: 10, Profile: "baseline", NoiseNamespaces: 2, NoiseMix: "small=3,large=1"})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(plan.Noise) != 2 || plan.Noise[1].Namespace != "deschedbench-20260209-000000-noise-2" {
+		t.Fatalf("unexpected noise workloads: %+v", plan.Noise)
+	}
+	for _, noise := range plan.Noise {
+		if _, ok := noise.PodLabels["deschedbench"]; ok {
+			t.Fatalf("noise pods must not match the policy label selector: %v", noise.PodLabels)
+		}
+		if noise.Mix["small"] != 3 || noise.Mix["large"] != 1 {
+			t.Fatalf("unexpected noise mix: %v", noise.Mix)
+		}
+	}
+	for _, cfg := range []RunConfig{
+		{PodsTotal: 10, Profile: "baseline", NoiseNamespaces: 1, NoiseMix: "huge=1"},
+		{PodsTotal: 10, Profile: "baseline", NoiseNamespaces: 1, NoiseMix: "small=0"},
+		{PodsTotal: 10, Profile: "baseline", NoiseNamespaces: -1},
+	} {
+		if _, err := builder.Build(cfg); err == nil {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+}
+
+func TestPlanBuilderMix(t *testing.T) {
+	builder := NewPlanBuilder()
+	plan, err := builder.Build(RunConfig{Profile: "baseline", PodCPU: "50m", PodMemory: "64Mi", Mix: "small=4,sts/medium=2,pod/small=1"})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if plan.Mix["small"] != 4 || plan.Mix["statefulset/medium"] != 2 || plan.Mix["pod/small"] != 1 {
+		t.Fatalf("unexpected mix: %v", plan.Mix)
+	}
+	if plan.SizeClasses["small"].CPU != "50m" || plan.SizeClasses["medium"].CPU != "250m" {
+		t.Fatalf("unexpected size classes: %v", plan.SizeClasses)
+	}
+	for _, cfg := range []RunConfig{
+		{Profile: "baseline", Mix: "sts/huge=1"},
+		{Profile: "baseline", Mix: "small=0"},
+		{Profile: "baseline"},
+	} {
+		if _, err := builder.Build(cfg); err == nil {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+}
+
+func TestPlanBuilderPriorityTiers(t *testing.T) {
+	builder := &PlanBuilder{
+		Now: func() time.Time {
+			return time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+		},
+	}
+	plan, err := builder.Build(RunConfig{Profile: "baseline", Mix: "small@low=4,sts/small@critical=2,medium=1"})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	want := map[string]string{"low": "deschedbench-20260209-000000-low", "critical": "deschedbench-20260209-000000-critical"}
+	if len(plan.PriorityClasses) != len(want) {
+		t.Fatalf("unexpected priority classes: %v", plan.PriorityClasses)
+	}
+	for tier, name := range want {
+		if plan.PriorityClasses[tier] != name {
+			t.Fatalf("unexpected priority classes: %v", plan.PriorityClasses)
+		}
+	}
+	plain, err := builder.Build(RunConfig{Profile: "baseline", Mix: "small=4"})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if plain.PriorityClasses != nil {
+		t.Fatalf("expected no priority classes without tiers, got %v", plain.PriorityClasses)
+	}
+}
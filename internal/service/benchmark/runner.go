@@ -7,28 +7,43 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"k8s-descheduler-benchmark/internal/benchmark"
+	"k8s-descheduler-benchmark/internal/descheduler"
+	"k8s-descheduler-benchmark/internal/grafana"
+	"k8s-descheduler-benchmark/internal/journal"
 	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/logging"
 	"k8s-descheduler-benchmark/internal/metrics"
+	"k8s-descheduler-benchmark/internal/promquery"
 	"k8s-descheduler-benchmark/internal/report"
 	"k8s-descheduler-benchmark/internal/service/cleanup"
+	"k8s-descheduler-benchmark/internal/store"
+	"k8s-descheduler-benchmark/internal/tracing"
+	"k8s-descheduler-benchmark/internal/workloads"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/kubernetes"
 )
 
 const (
 	scenarioName             = "maintenance"
 	deschedulerImagePinned   = "registry.k8s.io/descheduler/descheduler:v0.32.2"
-	deschedulerCronPinned    = "job"
+	defaultDeschedulerCron   = "*/1 * * * *"
+	defaultDeschedulerPeriod = time.Minute
 	defaultResultsDir        = "results"
 	defaultSampleInterval    = 5 * time.Second
 	defaultPostUncordonWait  = 60 * time.Second
 	defaultWaitTimeout       = 10 * time.Minute
 	defaultBalanceStddevGoal = 1.0
+	defaultDrainIterations   = 2
+	workloadImage            = "registry.k8s.io/pause:3.9"
 )
 
 type Runner struct {
@@ -36,21 +51,72 @@ type Runner struct {
 	Cleanup     *cleanup.CleanupService
 	Logger      *slog.Logger
 	MetricsPort int
+	// Grafana, when set, receives a region annotation per maintenance
+	// window and descheduler run.
+	Grafana *grafana.Client
+	// Prometheus, when set, evaluates PrometheusQueries over the run
+	// window after the run and embeds the results.
+	Prometheus        *promquery.Client
+	PrometheusQueries []promquery.Query
+	PrometheusStep    time.Duration
+	// Store, when set, archives every result so history and regress can
+	// compare runs.
+	Store *store.Store
 }
 
 type RunConfig struct {
-	PodsTotal  int32
-	PodCPU     string
-	PodMemory  string
-	Profile    string
+	PodsTotal           int32
+	PodCPU              string
+	PodMemory           string
+	Profile             string
+	DeschedulerImage    string
+	DeschedulerMode     string
+	DeschedulerCron     string
+	DeschedulerInterval time.Duration
+	OutputPath          string
+	// Exports lists extra formats (csv, ndjson) written next to the
+	// result file.
+	Exports []string
+	// Assertions are checked against the run summary; any failure makes
+	// Run return ErrAssertionsFailed after the result is written.
+	Assertions []report.Assertion
+	// JUnitPath, when set, receives a JUnit XML report of Assertions.
+	JUnitPath string
+	Context   string
+	Server    string
+	// NoiseNamespaces is how many namespaces of background Deployments run
+	// next to the benchmark, each with NoiseMix (small=N,medium=N,large=N).
+	// The descheduler policy only targets the benchmark namespace.
+	NoiseNamespaces int
+	NoiseMix        string
+	// Mix, when set, replaces PodsTotal with a workload mix that may span
+	// kinds, such as "small=40,sts/small=10,pod/small=2". The small class
+	// keeps PodCPU and PodMemory.
+	Mix string
+	// PriorityThreshold, when positive, is set as the DefaultEvictor
+	// priorityThreshold of the policy, protecting pods at or above it.
+	PriorityThreshold int32
+}
+
+// ErrAssertionsFailed is returned, wrapped, when a run completed but at
+// least one assertion did not hold.
+var ErrAssertionsFailed = errors.New("assertions failed")
+
+type runOutcome struct {
 	OutputPath string
-	Context    string
-	Server     string
+	ImageID    string
+	Summary    report.Summary
+	Evictions  int
 }
 
 func (r *Runner) Run(ctx context.Context, cfg RunConfig) error {
+	_, err := r.run(ctx, cfg)
+	return err
+}
+
+func (r *Runner) run(ctx context.Context, cfg RunConfig) (runOutcome, error) {
 	if r.Client == nil {
-		return fmt.Errorf("client is required")
+		return runOutcome{}, fmt.Errorf("client is required")
 	}
 	logger := r.Logger
 	if logger == nil {
@@ -61,16 +127,42 @@ func (r *Runner) Run(ctx context.Context, cfg RunConfig) error {
 		cleanupSvc = cleanup.NewCleanupService(r.Client, logger)
 	}
 	if err := cleanupSvc.Preflight(ctx); err != nil {
-		return err
+		return runOutcome{}, err
 	}
 
 	plan, err := NewPlanBuilder().Build(cfg)
 	if err != nil {
-		return err
+		return runOutcome{}, err
 	}
+	logger = logger.With(
+		logging.StringField("run_id", plan.RunID),
+		logging.StringField("profile", cfg.Profile),
+	)
+
+	journalWriter, err := journal.Create(journalDir(plan.OutputPath), journal.Journal{
+		RunID:      plan.RunID,
+		Scenario:   scenarioName,
+		Profile:    cfg.Profile,
+		Namespace:  plan.Namespace,
+		OutputPath: plan.OutputPath,
+		StartTime:  time.Now(),
+		Objects:    journalObjects(plan),
+		Config:     resultConfig(cfg, plan),
+	})
+	if err != nil {
+		return runOutcome{}, fmt.Errorf("create run journal: %w", err)
+	}
+	logger.Info("run journal", logging.StringField("path", journalWriter.Path()))
 
 	ctxRun, cancel := context.WithCancel(ctx)
 	defer cancel()
+	ctxRun, runSpan := tracing.Tracer().Start(ctxRun, "benchmark", trace.WithAttributes(
+		attribute.String("run_id", plan.RunID),
+		attribute.String("scenario", scenarioName),
+		attribute.String("profile", cfg.Profile),
+		attribute.String("descheduler.image", plan.DeschedulerImage),
+		attribute.Int("pods", int(workloads.MixTotal(plan.Mix))),
+	))
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
@@ -92,12 +184,16 @@ func (r *Runner) Run(ctx context.Context, cfg RunConfig) error {
 			defer cancelCleanup()
 			if err := cleanupSvc.Run(ctxCleanup, cleanup.Scope{
 				Namespace: plan.Namespace,
+				RunID:     plan.RunID,
 				Wait:      true,
 			}); err != nil {
 				logger.Error("cleanup failed", logging.ErrorField(err))
 				return
 			}
 			logger.Info("namespace cleanup done", logging.StringField("namespace", plan.Namespace))
+			if err := journalWriter.Update(func(j *journal.Journal) { j.Status = journal.StatusCleaned }); err != nil {
+				logger.Warn("run journal update failed", logging.ErrorField(err))
+			}
 		})
 	}
 	defer runCleanup("defer")
@@ -123,41 +219,69 @@ func (r *Runner) Run(ctx context.Context, cfg RunConfig) error {
 		NamespaceOnly: true,
 	}, logger)
 
+	annotator := newStepAnnotator(r.Grafana, plan.RunID, cfg.Profile, logger)
+
 	logger.Info("starting maintenance scenario")
-	result, err := benchmark.RunMaintenance(ctxRun, r.Client, benchmark.MaintenanceConfig{
+	windowStart := time.Now()
+	result, runErr := benchmark.RunMaintenance(ctxRun, r.Client, benchmark.MaintenanceConfig{
 		RunID:         plan.RunID,
+		Profile:       cfg.Profile,
 		Namespace:     plan.Namespace,
-		WorkloadImage: "registry.k8s.io/pause:3.9",
+		WorkloadImage: workloadImage,
 		WorkloadMix:   plan.Mix,
 		SizeClasses:   plan.SizeClasses,
 		LabelSelector: plan.LabelSelector,
 		Labels:        plan.Labels,
 		RecordPhase: func(name string) error {
-			return phaseRec.Record(ctxRun, name)
+			if err := phaseRec.Record(ctxRun, name); err != nil {
+				return err
+			}
+			return journalWriter.Update(func(j *journal.Journal) {
+				j.Phase = name
+				j.Phases = phaseRec.Phases()
+				j.Samples = sampler.Samples()
+			})
 		},
-		WaitTimeout:       defaultWaitTimeout,
-		PostUncordonWait:  defaultPostUncordonWait,
-		DrainIterations:   2,
-		DeschedulerImage:  deschedulerImagePinned,
-		DeschedulerNS:     plan.Namespace,
-		DeschedulerPolicy: plan.PolicyYAML,
-		DeschedulerCron:   deschedulerCronPinned,
+		RecordStep: annotator.Record,
+		RecordCordon: func(node string, cordoned bool) error {
+			if cordoned {
+				return journalWriter.Cordoned(node)
+			}
+			return journalWriter.Restored(node)
+		},
+		WaitTimeout:         defaultWaitTimeout,
+		PostUncordonWait:    defaultPostUncordonWait,
+		DrainIterations:     defaultDrainIterations,
+		DeschedulerImage:    plan.DeschedulerImage,
+		DeschedulerNS:       plan.Namespace,
+		DeschedulerPolicy:   plan.PolicyYAML,
+		DeschedulerMode:     plan.DeschedulerMode,
+		DeschedulerCron:     plan.DeschedulerCron,
+		DeschedulerInterval: plan.DeschedulerInterval,
+		Noise:               plan.Noise,
+		PriorityClasses:     plan.PriorityClasses,
 	})
-	if err != nil {
+	status := report.StatusSuccess
+	if runErr != nil {
 		metrics.ErrorsTotal.WithLabelValues("scenario").Inc()
-		if errors.Is(err, context.Canceled) {
-			runCleanup("cancel")
-			return err
+		status = report.StatusFailed
+		if errors.Is(runErr, context.Canceled) {
+			status = report.StatusCancelled
 		}
-		runCleanup("error")
-		return err
 	}
+	runSpan.SetAttributes(
+		attribute.String("status", status),
+		attribute.Int("evictions", len(result.Evictions)),
+	)
+	tracing.End(runSpan, runErr)
 
 	cancel()
+	enrichment := r.enrich(windowStart, time.Now(), logger)
 	samples := sampler.Samples()
 	phases := phaseRec.Phases()
 	beforeSnap, beforeSample := phaseRec.Before()
 	afterSnap, afterSample := phaseRec.After()
+	clusterBefore, clusterAfter := phaseRec.Cluster()
 
 	rebalanceTime := computeRebalanceTime(samples, phases, defaultBalanceStddevGoal)
 	summary := report.Summary{
@@ -169,52 +293,243 @@ func (r *Runner) Run(ctx context.Context, cfg RunConfig) error {
 		Before:               beforeSample,
 		After:                afterSample,
 	}
-
-	config := report.RunConfig{
-		RunID:                plan.RunID,
-		Scenario:             scenarioName,
-		Profile:              cfg.Profile,
-		Namespace:            plan.Namespace,
-		StartTime:            time.Now(),
-		Context:              cfg.Context,
-		Server:               cfg.Server,
-		PodsTotal:            cfg.PodsTotal,
-		PodCPU:               cfg.PodCPU,
-		PodMemory:            cfg.PodMemory,
-		DeschedulerImage:     deschedulerImagePinned,
-		DeschedulerNamespace: plan.Namespace,
-		DeschedulerCron:      deschedulerCronPinned,
-		SampleInterval:       defaultSampleInterval.String(),
-		SampleDuration:       "0s",
+	summary.StrandedPods, summary.StrandedStatefulPods = report.CountStranded(result.Evictions)
+	if len(plan.PriorityClasses) > 0 {
+		summary.Priority = report.NewPrioritySummary(result.Disruptions)
+	}
+	if len(clusterBefore.Nodes) > 0 && len(clusterAfter.Nodes) > 0 {
+		summary.ClusterWide = report.NewClusterBalance(clusterBefore, clusterAfter)
 	}
 
-	output := struct {
-		Config         report.RunConfig     `json:"config"`
-		Phases         []report.PhaseMarker `json:"phases"`
-		Summary        report.Summary       `json:"summary"`
-		Samples        []metrics.Sample     `json:"samples"`
-		BeforeSnapshot metrics.Snapshot     `json:"before_snapshot"`
-		AfterSnapshot  metrics.Snapshot     `json:"after_snapshot"`
-		Evictions      []k8s.EvictionRecord `json:"evictions"`
-	}{
+	config := resultConfig(cfg, plan)
+	config.DeschedulerImageID = result.DeschedulerImageID
+
+	output := report.Result{
+		Status:         status,
 		Config:         config,
 		Phases:         phases,
 		Summary:        summary,
 		Samples:        samples,
+		Snapshots:      sampler.Snapshots(),
 		BeforeSnapshot: beforeSnap,
 		AfterSnapshot:  afterSnap,
 		Evictions:      result.Evictions,
+		Latency:        report.SummarizeLatency(result.PodLatencies, result.Evictions),
+		Activity:       result.DeschedulerActivity,
+		Prometheus:     enrichment,
+		Disruptions:    result.Disruptions,
+	}
+	if summary.ClusterWide != nil {
+		output.ClusterBeforeSnapshot = &clusterBefore
+		output.ClusterAfterSnapshot = &clusterAfter
+	}
+	if runErr != nil {
+		output.Error = runErr.Error()
+		output.FailedPhase = result.LastPhase
+		// Diagnose before cleanup deletes the namespace.
+		output.Diagnosis = diagnose(r.Client, plan)
+	}
+	if len(cfg.Assertions) > 0 {
+		if runErr != nil {
+			output.Assertions = report.AssertionErrors(cfg.Assertions, runErr)
+		} else {
+			output.Assertions = report.Evaluate(cfg.Assertions, report.SummaryValues(summary, len(result.Evictions)))
+		}
 	}
 
-	if err := report.WriteJSON(plan.OutputPath, output); err != nil {
+	outcome := runOutcome{
+		OutputPath: plan.OutputPath,
+		ImageID:    result.DeschedulerImageID,
+		Summary:    summary,
+		Evictions:  len(result.Evictions),
+	}
+	if err := report.WriteResult(plan.OutputPath, output); err != nil {
 		runCleanup("error")
-		return err
+		if runErr != nil {
+			logger.Error("results output failed", logging.ErrorField(err))
+			return outcome, runErr
+		}
+		return runOutcome{}, err
+	}
+	r.export(plan.OutputPath, output, cfg.Exports, logger)
+	r.archive(output, logger)
+	if cfg.JUnitPath != "" {
+		if err := report.WriteJUnit(cfg.JUnitPath, summary, windowStart, output.Assertions); err != nil {
+			logger.Error("junit output failed", logging.ErrorField(err))
+		} else {
+			logger.Info("junit output", logging.StringField("path", cfg.JUnitPath))
+		}
+	}
+	if runErr != nil {
+		logger.Error("benchmark failed",
+			logging.StringField("status", status),
+			logging.StringField("phase", result.LastPhase),
+			logging.ErrorField(runErr),
+		)
+		if status == report.StatusCancelled {
+			runCleanup("cancel")
+		} else {
+			runCleanup("error")
+		}
+		logger.Info("partial results output", logging.StringField("path", plan.OutputPath))
+		return outcome, runErr
 	}
 
 	metrics.TotalDuration.WithLabelValues(scenarioName, cfg.Profile, plan.RunID).Set(result.Duration.Seconds())
 	logSummary(summary, beforeSnap, afterSnap)
+	logClusterBalance(summary.ClusterWide, clusterBefore, clusterAfter)
+	logPriority(summary.Priority)
+	logLatency(output.Latency)
+	logAssertions(logger, output.Assertions)
 	logger.Info("benchmark completed")
 	runCleanup("success")
 	logger.Info("results output", logging.StringField("path", plan.OutputPath))
-	return nil
+	if failed := report.FailedAssertions(output.Assertions); failed > 0 {
+		return outcome, fmt.Errorf("%d of %d assertions failed: %w", failed, len(output.Assertions), ErrAssertionsFailed)
+	}
+	return outcome, nil
+}
+
+// archive adds output to the results store. Failures are logged; the
+// result file is already written.
+func (r *Runner) archive(output report.Result, logger *slog.Logger) {
+	if r.Store == nil {
+		return
+	}
+	entry, err := r.Store.Add(output)
+	if err != nil {
+		logger.Error("results store failed", logging.StringField("dir", r.Store.Dir()), logging.ErrorField(err))
+		return
+	}
+	logger.Info("results stored",
+		logging.StringField("path", filepath.Join(r.Store.Dir(), entry.Path)),
+		logging.StringField("config", entry.Config),
+	)
+}
+
+// export writes the flat tables of output in each requested format next to
+// the result file. Export failures are logged; the JSON result is already
+// written.
+func (r *Runner) export(path string, output report.Result, formats []string, logger *slog.Logger) {
+	for _, format := range formats {
+		paths, err := report.Export(report.ExportDir(path), output, format)
+		if err != nil {
+			logger.Error("results export failed",
+				logging.StringField("format", format),
+				logging.ErrorField(err),
+			)
+			continue
+		}
+		logger.Info("results exported",
+			logging.StringField("format", format),
+			logging.StringField("files", strings.Join(paths, ",")),
+		)
+	}
+}
+
+// enrich runs the Prometheus range queries over the run window. The run
+// context is cancelled by now, so it uses its own.
+func (r *Runner) enrich(start, end time.Time, logger *slog.Logger) *promquery.Enrichment {
+	if r.Prometheus == nil {
+		return nil
+	}
+	queries := r.PrometheusQueries
+	if len(queries) == 0 {
+		queries = promquery.DefaultQueries()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	enrichment := r.Prometheus.Enrich(ctx, queries, promquery.Window{
+		Start: start,
+		End:   end,
+		Step:  r.PrometheusStep.String(),
+	})
+	for _, q := range enrichment.Queries {
+		if q.Error != "" {
+			logger.Warn("prometheus query failed",
+				logging.StringField("query", q.Name),
+				logging.StringField("error", q.Error),
+			)
+		}
+	}
+	logger.Info("prometheus enrichment done", logging.StringField("queries", fmt.Sprintf("%d", len(enrichment.Queries))))
+	return &enrichment
+}
+
+// diagnose summarizes why benchmark pods were not ready. It uses its own
+// context because the run context is usually cancelled by now.
+func diagnose(client kubernetes.Interface, plan Plan) *report.Diagnosis {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	summary, err := k8s.SummarizeScheduling(ctx, client, plan.Namespace, plan.LabelSelector)
+	if err != nil {
+		return nil
+	}
+	diagnosis := &report.Diagnosis{
+		Ready:   summary.Ready,
+		Pending: summary.Pending,
+		Reasons: summary.Messages,
+	}
+	if nodes, err := k8s.ListNodes(ctx, client, ""); err == nil {
+		diagnosis.UnschedulableNodes = k8s.UnschedulableNodeNames(nodes)
+	}
+	return diagnosis
+}
+
+// journalDir keeps run journals next to the results so `recover` finds them
+// with the same --out directory.
+func journalDir(outputPath string) string {
+	return filepath.Join(filepath.Dir(outputPath), ".journal")
+}
+
+// journalObjects lists the cluster objects a run of plan creates.
+func journalObjects(plan Plan) []journal.Object {
+	objects := []journal.Object{{Kind: "namespace", Name: plan.Namespace}}
+	for _, noise := range plan.Noise {
+		objects = append(objects, journal.Object{Kind: "namespace", Name: noise.Namespace})
+	}
+	for _, tier := range workloads.MixTiers(plan.Mix) {
+		objects = append(objects, journal.Object{Kind: "priorityclass", Name: plan.PriorityClasses[tier]})
+	}
+	if plan.PolicyYAML != "" {
+		objects = append(objects,
+			journal.Object{Kind: "clusterrole", Name: descheduler.RBACName(plan.RunID)},
+			journal.Object{Kind: "clusterrolebinding", Name: descheduler.RBACName(plan.RunID)},
+		)
+	}
+	return objects
+}
+
+// resultConfig records cfg and plan as the config of the result file.
+func resultConfig(cfg RunConfig, plan Plan) report.RunConfig {
+	config := report.RunConfig{
+		RunID:                plan.RunID,
+		Scenario:             scenarioName,
+		Profile:              cfg.Profile,
+		Namespace:            plan.Namespace,
+		StartTime:            time.Now(),
+		Context:              cfg.Context,
+		Server:               cfg.Server,
+		PodsTotal:            workloads.MixTotal(plan.Mix),
+		PodCPU:               cfg.PodCPU,
+		PodMemory:            cfg.PodMemory,
+		DeschedulerImage:     plan.DeschedulerImage,
+		DeschedulerNamespace: plan.Namespace,
+		DeschedulerMode:      plan.DeschedulerMode,
+		DeschedulerCron:      plan.DeschedulerCron,
+		DeschedulerInterval:  plan.DeschedulerInterval.String(),
+		SampleInterval:       defaultSampleInterval.String(),
+		SampleDuration:       "0s",
+	}
+	if len(plan.Noise) > 0 {
+		config.NoiseNamespaces = len(plan.Noise)
+		config.NoiseMix = plan.Noise[0].Mix.String()
+	}
+	if cfg.Mix != "" {
+		config.Mix = plan.Mix.String()
+	}
+	if cfg.PriorityThreshold > 0 && plan.PolicyYAML != "" {
+		config.PriorityThreshold = cfg.PriorityThreshold
+	}
+	return config
 }
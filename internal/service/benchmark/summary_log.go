@@ -2,6 +2,7 @@ package benchmark
 
 import (
 	"fmt"
+	"log/slog"
 
 	"k8s-descheduler-benchmark/internal/logging"
 	"k8s-descheduler-benchmark/internal/metrics"
@@ -18,4 +19,110 @@ func logSummary(summary report.Summary, before metrics.Snapshot, after metrics.S
 		logging.StringField("before_pods", report.FormatNodePods(before)),
 		logging.StringField("after_pods", report.FormatNodePods(after)),
 	)
+	if summary.StrandedPods > 0 {
+		logger.Warn("evicted pods without a ready replacement",
+			logging.StringField("stranded_pods", fmt.Sprintf("%d", summary.StrandedPods)),
+			logging.StringField("stranded_stateful_pods", fmt.Sprintf("%d", summary.StrandedStatefulPods)),
+		)
+	}
+}
+
+// logClusterBalance reports balance with every namespace counted, next to
+// the benchmark-only numbers of logSummary.
+func logClusterBalance(balance *report.ClusterBalance, before, after metrics.Snapshot) {
+	if balance == nil {
+		return
+	}
+	logging.GetLogger().Info("cluster-wide balance",
+		logging.StringField("before_pods_stddev", fmt.Sprintf("%.3f", balance.Before.PodsStddev)),
+		logging.StringField("after_pods_stddev", fmt.Sprintf("%.3f", balance.After.PodsStddev)),
+		logging.StringField("before_cpu_requested_pct_stddev", fmt.Sprintf("%.1f", balance.BeforeCPURequestedStddev)),
+		logging.StringField("after_cpu_requested_pct_stddev", fmt.Sprintf("%.1f", balance.AfterCPURequestedStddev)),
+		logging.StringField("before_pods", report.FormatNodePods(before)),
+		logging.StringField("after_pods", report.FormatNodePods(after)),
+	)
+}
+
+// logPriority reports disruptions per priority tier, one line per trigger
+// and cause.
+func logPriority(priority *report.PrioritySummary) {
+	if priority == nil {
+		return
+	}
+	logger := logging.GetLogger()
+	for _, row := range []struct {
+		trigger, cause string
+		counts         report.TierCounts
+	}{
+		{"drain", "evicted", priority.DrainEvicted},
+		{"drain", "preempted", priority.DrainPreempted},
+		{"descheduler", "evicted", priority.DeschedulerEvicted},
+		{"descheduler", "preempted", priority.DeschedulerPreempted},
+	} {
+		logger.Info("disruptions by priority tier",
+			logging.StringField("trigger", row.trigger),
+			logging.StringField("cause", row.cause),
+			logging.StringField("critical", fmt.Sprintf("%d", row.counts.Critical)),
+			logging.StringField("high", fmt.Sprintf("%d", row.counts.High)),
+			logging.StringField("low", fmt.Sprintf("%d", row.counts.Low)),
+			logging.StringField("none", fmt.Sprintf("%d", row.counts.None)),
+		)
+	}
+}
+
+func logLatency(latency *report.LatencyReport) {
+	if latency == nil {
+		return
+	}
+	logger := logging.GetLogger()
+	for _, t := range latency.Triggers {
+		logger.Info("pod startup latency",
+			logging.StringField("trigger", t.Trigger),
+			logging.StringField("pods", fmt.Sprintf("%d", t.Pods)),
+			logging.StringField("scheduled_p50_p99", formatLatency(t.CreationToScheduled)),
+			logging.StringField("running_p50_p99", formatLatency(t.ScheduledToRunning)),
+			logging.StringField("ready_p50_p99", formatLatency(t.RunningToReady)),
+		)
+	}
+	if latency.Reschedule.Count > 0 {
+		logger.Info("reschedule latency",
+			logging.StringField("evictions", fmt.Sprintf("%d", latency.Reschedule.Count)),
+			logging.StringField("p50_p99", formatLatency(latency.Reschedule)),
+		)
+	}
+}
+
+func formatLatency(stats report.LatencyStats) string {
+	return fmt.Sprintf("%.1fs/%.1fs", stats.P50, stats.P99)
+}
+
+func logAssertions(logger *slog.Logger, results []report.AssertionResult) {
+	for _, res := range results {
+		if res.Passed {
+			logger.Info("assertion passed", logging.StringField("assert", res.Expr), logging.StringField("actual", fmt.Sprint(res.Actual)))
+			continue
+		}
+		logger.Error("assertion failed", logging.StringField("assert", res.Expr), logging.StringField("reason", res.Message()))
+	}
+}
+
+func logImageComparison(comparison report.ImageComparison) {
+	logger := logging.GetLogger()
+	for _, row := range comparison.Rows {
+		if row.Error != "" {
+			logger.Info("image comparison",
+				logging.StringField("image", row.Image),
+				logging.StringField("status", "failed"),
+				logging.StringField("error", row.Error),
+			)
+			continue
+		}
+		logger.Info("image comparison",
+			logging.StringField("image", row.Image),
+			logging.StringField("image_id", row.ImageID),
+			logging.StringField("after_pods_stddev", fmt.Sprintf("%.3f (%+.3f)", row.Summary.After.PodsStddev, row.AfterStddevDelta)),
+			logging.StringField("rebalance_time", fmt.Sprintf("%.1fs (%+.1fs)", row.Summary.RebalanceTimeSeconds, row.RebalanceTimeDelta)),
+			logging.StringField("evictions", fmt.Sprintf("%d (%+d)", row.Evictions, row.EvictionsDelta)),
+		)
+	}
 }
@@ -10,14 +10,45 @@ import (
 	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/logging"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// Scope selects what cleanup removes. With RunID set, cluster-scoped objects
+// and cordoned nodes are only touched when their run ID annotation matches, so
+// a concurrent run keeps its RBAC. Namespace with RunID also removes the other
+// managed namespaces of that run. AllNodes uncordons every unschedulable node,
+// including ones cordoned outside deschedbench.
 type Scope struct {
 	Namespace       string
 	NamespacePrefix string
+	RunID           string
 	Wait            bool
+	DryRun          bool
+	AllNodes        bool
+}
+
+const (
+	VerbDelete   = "delete"
+	VerbRestore  = "restore"
+	VerbUncordon = "uncordon"
+)
+
+// Action is a single change cleanup makes, or would make in a dry run.
+type Action struct {
+	Verb  string `json:"verb"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	RunID string `json:"run_id,omitempty"`
+}
+
+func (a Action) String() string {
+	out := fmt.Sprintf("%s %s/%s", a.Verb, a.Kind, a.Name)
+	if a.RunID != "" {
+		out += fmt.Sprintf(" (run %s)", a.RunID)
+	}
+	return out
 }
 
 type CleanupService struct {
@@ -35,102 +66,231 @@ func NewCleanupService(client kubernetes.Interface, logger *slog.Logger) *Cleanu
 	}
 }
 
+// Preflight refuses to start while a node is still cordoned by an earlier
+// deschedbench run. Nodes cordoned by others are only logged: the benchmark
+// never drains them and cleanup leaves them alone.
 func (s *CleanupService) Preflight(ctx context.Context) error {
 	nodes, err := k8s.ListNodes(ctx, s.client, "")
 	if err != nil {
 		return err
 	}
-	unschedulable := make([]string, 0, len(nodes))
+	ours := make([]string, 0, len(nodes))
 	for _, node := range nodes {
 		if !node.Spec.Unschedulable {
 			continue
 		}
-		if isControlPlaneNode(node.Labels) {
+		if k8s.IsControlPlaneNode(node.Labels) {
 			continue
 		}
-		unschedulable = append(unschedulable, node.Name)
+		if runID, ok := k8s.CordonedBy(node); ok {
+			ours = append(ours, fmt.Sprintf("%s (run %s)", node.Name, runID))
+			continue
+		}
+		s.logger.Warn("node cordoned outside deschedbench, excluded from benchmark", logging.StringField("name", node.Name))
 	}
-	if len(unschedulable) > 0 {
-		return fmt.Errorf("refusing to run: nodes unschedulable before benchmark: %s. Run `make cleanup`", strings.Join(unschedulable, ", "))
+	if len(ours) > 0 {
+		return fmt.Errorf("refusing to run: nodes still cordoned by deschedbench: %s. Run `make cleanup`", strings.Join(ours, ", "))
 	}
 	return nil
 }
 
 func (s *CleanupService) Run(ctx context.Context, scope Scope) error {
-	if err := s.cleanupNamespaces(ctx, scope); err != nil {
+	actions, err := s.Plan(ctx, scope)
+	if err != nil {
 		return err
 	}
-	if err := s.uncordonNodes(ctx); err != nil {
-		return err
+	if scope.DryRun {
+		for _, action := range actions {
+			s.logger.Info("cleanup dry run", logging.StringField("action", action.String()))
+		}
+		return nil
 	}
-	return nil
+	return s.Apply(ctx, actions, scope.Wait)
 }
 
-func (s *CleanupService) cleanupNamespaces(ctx context.Context, scope Scope) error {
-	switch {
-	case scope.Namespace != "":
-		if err := k8s.DeleteNamespace(ctx, s.client, scope.Namespace); err != nil {
+// Apply takes actions in order, such as a plan extended by the caller.
+func (s *CleanupService) Apply(ctx context.Context, actions []Action, wait bool) error {
+	for _, action := range actions {
+		if err := s.apply(ctx, action, wait); err != nil {
 			return err
 		}
-		if scope.Wait {
-			if err := k8s.WaitForNamespaceDeleted(ctx, s.client, scope.Namespace, 10*time.Minute); err != nil {
-				return err
-			}
-		}
-		return nil
-	case scope.NamespacePrefix != "":
-		return s.cleanupNamespacePrefix(ctx, scope.NamespacePrefix, scope.Wait)
-	default:
-		return s.cleanupNamespacePrefix(ctx, "deschedbench-", scope.Wait)
 	}
+	return nil
 }
 
-func (s *CleanupService) cleanupNamespacePrefix(ctx context.Context, prefix string, wait bool) error {
-	namespaces, err := s.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+// Plan lists the actions Run would take for scope without changing anything.
+// Namespaces go first so the descheduler stops before its RBAC is removed.
+func (s *CleanupService) Plan(ctx context.Context, scope Scope) ([]Action, error) {
+	var actions []Action
+	namespaces, err := s.planNamespaces(ctx, scope)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	actions = append(actions, namespaces...)
+	rbac, err := s.planClusterRBAC(ctx, scope.RunID)
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions, rbac...)
+	priorityClasses, err := s.planPriorityClasses(ctx, scope.RunID)
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions, priorityClasses...)
+	nodes, err := s.planNodes(ctx, scope)
+	if err != nil {
+		return nil, err
 	}
+	actions = append(actions, nodes...)
+	return actions, nil
+}
 
-	for _, ns := range namespaces.Items {
-		if !strings.HasPrefix(ns.Name, prefix) {
+func (s *CleanupService) planNodes(ctx context.Context, scope Scope) ([]Action, error) {
+	nodes, err := k8s.ListNodes(ctx, s.client, "")
+	if err != nil {
+		return nil, err
+	}
+	var actions []Action
+	if scope.AllNodes {
+		for _, name := range k8s.UnschedulableNodeNames(nodes) {
+			actions = append(actions, Action{Verb: VerbUncordon, Kind: "node", Name: name})
+		}
+		return actions, nil
+	}
+	for _, node := range nodes {
+		runID, ok := k8s.CordonedBy(node)
+		if !ok || (scope.RunID != "" && runID != scope.RunID) {
 			continue
 		}
-		if err := k8s.DeleteNamespace(ctx, s.client, ns.Name); err != nil {
-			return err
+		actions = append(actions, Action{Verb: VerbRestore, Kind: "node", Name: node.Name, RunID: runID})
+	}
+	return actions, nil
+}
+
+func (s *CleanupService) planNamespaces(ctx context.Context, scope Scope) ([]Action, error) {
+	if scope.Namespace != "" {
+		var actions []Action
+		ns, err := s.client.CoreV1().Namespaces().Get(ctx, scope.Namespace, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
 		}
-		if wait {
-			if err := k8s.WaitForNamespaceDeleted(ctx, s.client, ns.Name, 10*time.Minute); err != nil {
-				return err
+		if err == nil {
+			actions = append(actions, Action{Verb: VerbDelete, Kind: "namespace", Name: ns.Name, RunID: k8s.RunIDOf(ns)})
+		}
+		if scope.RunID == "" {
+			return actions, nil
+		}
+		// Other namespaces the run created, such as noise workloads.
+		owned, err := s.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: k8s.ManagedSelector})
+		if err != nil {
+			return nil, err
+		}
+		for i := range owned.Items {
+			ns := &owned.Items[i]
+			if ns.Name == scope.Namespace || k8s.RunIDOf(ns) != scope.RunID {
+				continue
 			}
+			actions = append(actions, Action{Verb: VerbDelete, Kind: "namespace", Name: ns.Name, RunID: scope.RunID})
 		}
+		return actions, nil
 	}
-	return nil
+	prefix := scope.NamespacePrefix
+	if prefix == "" {
+		prefix = "deschedbench-"
+	}
+	namespaces, err := s.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var actions []Action
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if !strings.HasPrefix(ns.Name, prefix) {
+			continue
+		}
+		actions = append(actions, Action{Verb: VerbDelete, Kind: "namespace", Name: ns.Name, RunID: k8s.RunIDOf(ns)})
+	}
+	return actions, nil
 }
 
-func (s *CleanupService) uncordonNodes(ctx context.Context) error {
-	nodes, err := k8s.ListNodes(ctx, s.client, "")
+func (s *CleanupService) planClusterRBAC(ctx context.Context, runID string) ([]Action, error) {
+	opts := metav1.ListOptions{LabelSelector: k8s.ManagedSelector}
+	var actions []Action
+	bindings, err := s.client.RbacV1().ClusterRoleBindings().List(ctx, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	unschedulable := k8s.UnschedulableNodeNames(nodes)
-	for _, name := range unschedulable {
-		s.logger.Info("uncordon node", logging.StringField("name", name))
-		if err := k8s.UncordonNode(ctx, s.client, name); err != nil {
-			return err
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		if runID != "" && k8s.RunIDOf(binding) != runID {
+			continue
 		}
+		actions = append(actions, Action{Verb: VerbDelete, Kind: "clusterrolebinding", Name: binding.Name, RunID: k8s.RunIDOf(binding)})
 	}
-	return nil
+	roles, err := s.client.RbacV1().ClusterRoles().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles.Items {
+		role := &roles.Items[i]
+		if runID != "" && k8s.RunIDOf(role) != runID {
+			continue
+		}
+		actions = append(actions, Action{Verb: VerbDelete, Kind: "clusterrole", Name: role.Name, RunID: k8s.RunIDOf(role)})
+	}
+	return actions, nil
 }
 
-func isControlPlaneNode(labels map[string]string) bool {
-	if labels == nil {
-		return false
+// planPriorityClasses lists the managed PriorityClasses, only those of runID
+// when it is set. They go after the namespaces, once no pod uses them.
+func (s *CleanupService) planPriorityClasses(ctx context.Context, runID string) ([]Action, error) {
+	classes, err := s.client.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{LabelSelector: k8s.ManagedSelector})
+	if err != nil {
+		return nil, err
 	}
-	if _, ok := labels["node-role.kubernetes.io/control-plane"]; ok {
-		return true
+	var actions []Action
+	for i := range classes.Items {
+		pc := &classes.Items[i]
+		if runID != "" && k8s.RunIDOf(pc) != runID {
+			continue
+		}
+		actions = append(actions, Action{Verb: VerbDelete, Kind: "priorityclass", Name: pc.Name, RunID: k8s.RunIDOf(pc)})
 	}
-	if _, ok := labels["node-role.kubernetes.io/master"]; ok {
-		return true
+	return actions, nil
+}
+
+func (s *CleanupService) apply(ctx context.Context, action Action, wait bool) error {
+	var err error
+	switch action.Kind {
+	case "namespace":
+		if err := k8s.DeleteNamespace(ctx, s.client, action.Name); err != nil {
+			return err
+		}
+		if wait {
+			return k8s.WaitForNamespaceDeleted(ctx, s.client, action.Name, 10*time.Minute)
+		}
+		return nil
+	case "clusterrolebinding":
+		s.logger.Info("delete cluster role binding", logging.StringField("name", action.Name))
+		err = s.client.RbacV1().ClusterRoleBindings().Delete(ctx, action.Name, metav1.DeleteOptions{})
+	case "clusterrole":
+		s.logger.Info("delete cluster role", logging.StringField("name", action.Name))
+		err = s.client.RbacV1().ClusterRoles().Delete(ctx, action.Name, metav1.DeleteOptions{})
+	case "priorityclass":
+		s.logger.Info("delete priority class", logging.StringField("name", action.Name))
+		err = s.client.SchedulingV1().PriorityClasses().Delete(ctx, action.Name, metav1.DeleteOptions{})
+	case "node":
+		if action.Verb == VerbRestore {
+			s.logger.Info("restore node", logging.StringField("name", action.Name))
+			return k8s.RestoreNode(ctx, s.client, action.Name)
+		}
+		s.logger.Info("uncordon node", logging.StringField("name", action.Name))
+		return k8s.UncordonNode(ctx, s.client, action.Name)
+	default:
+		return fmt.Errorf("unsupported cleanup action %s", action)
 	}
-	return false
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	return nil
 }
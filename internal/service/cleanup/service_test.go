@@ -4,14 +4,38 @@ import (
 	"context"
 	"testing"
 
+	"k8s-descheduler-benchmark/internal/k8s"
 	"k8s-descheduler-benchmark/internal/logging"
 
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
-func TestPreflightFailsWhenUnschedulableNode(t *testing.T) {
+func cordonedNode(name, runID string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				"deschedbench/cordoned-by":            runID,
+				"deschedbench/previous-unschedulable": "false",
+			},
+		},
+		Spec: corev1.NodeSpec{Unschedulable: true},
+	}
+}
+
+func TestPreflightFailsWhenNodeCordonedByDeschedbench(t *testing.T) {
+	client := fake.NewSimpleClientset(cordonedNode("node-1", "run-a"))
+	service := NewCleanupService(client, logging.GetLogger())
+	if err := service.Preflight(context.Background()); err == nil {
+		t.Fatalf("expected error for node left cordoned by deschedbench")
+	}
+}
+
+func TestPreflightIgnoresNodesCordonedByOthers(t *testing.T) {
 	client := fake.NewSimpleClientset(
 		&corev1.Node{
 			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
@@ -19,8 +43,8 @@ func TestPreflightFailsWhenUnschedulableNode(t *testing.T) {
 		},
 	)
 	service := NewCleanupService(client, logging.GetLogger())
-	if err := service.Preflight(context.Background()); err == nil {
-		t.Fatalf("expected error for unschedulable node")
+	if err := service.Preflight(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
 	}
 }
 
@@ -70,7 +94,60 @@ func TestRunCleansByPrefix(t *testing.T) {
 	}
 }
 
-func TestRunUncordonsNodes(t *testing.T) {
+func TestRunDeletesOtherNamespacesOfRun(t *testing.T) {
+	managed := func(name, runID string) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		k8s.MarkManaged(&ns.ObjectMeta, runID)
+		return ns
+	}
+	client := fake.NewSimpleClientset(
+		managed("deschedbench-a", "run-a"),
+		managed("deschedbench-a-noise-1", "run-a"),
+		managed("deschedbench-b-noise-1", "run-b"),
+	)
+	service := NewCleanupService(client, logging.GetLogger())
+	actions, err := service.Plan(context.Background(), Scope{Namespace: "deschedbench-a", RunID: "run-a"})
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	var names []string
+	for _, action := range actions {
+		names = append(names, action.Name)
+	}
+	if len(names) != 2 || names[0] != "deschedbench-a" || names[1] != "deschedbench-a-noise-1" {
+		t.Fatalf("expected run namespace then noise namespace, got %v", names)
+	}
+}
+
+func TestRunRestoresOnlyOwnCordons(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		cordonedNode("ours", "run-a"),
+		cordonedNode("other-run", "run-b"),
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "operator"},
+			Spec:       corev1.NodeSpec{Unschedulable: true},
+		},
+	)
+	service := NewCleanupService(client, logging.GetLogger())
+	if err := service.Run(context.Background(), Scope{Namespace: "deschedbench-a", RunID: "run-a"}); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+	for name, want := range map[string]bool{"ours": false, "other-run": true, "operator": true} {
+		node, err := client.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get node %s: %v", name, err)
+		}
+		if node.Spec.Unschedulable != want {
+			t.Fatalf("node %s: expected unschedulable=%v", name, want)
+		}
+	}
+	node, _ := client.CoreV1().Nodes().Get(context.Background(), "ours", metav1.GetOptions{})
+	if _, ok := node.Annotations["deschedbench/cordoned-by"]; ok {
+		t.Fatalf("expected cordon annotations removed")
+	}
+}
+
+func TestRunUncordonsAllNodes(t *testing.T) {
 	client := fake.NewSimpleClientset(
 		&corev1.Node{
 			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
@@ -78,7 +155,7 @@ func TestRunUncordonsNodes(t *testing.T) {
 		},
 	)
 	service := NewCleanupService(client, logging.GetLogger())
-	if err := service.Run(context.Background(), Scope{NamespacePrefix: "deschedbench-", Wait: false}); err != nil {
+	if err := service.Run(context.Background(), Scope{NamespacePrefix: "deschedbench-", AllNodes: true}); err != nil {
 		t.Fatalf("cleanup failed: %v", err)
 	}
 	node, err := client.CoreV1().Nodes().Get(context.Background(), "node-1", metav1.GetOptions{})
@@ -89,3 +166,90 @@ func TestRunUncordonsNodes(t *testing.T) {
 		t.Fatalf("expected node to be uncordoned")
 	}
 }
+
+func managedClusterRole(name, runID string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Labels:      map[string]string{"deschedbench": "true"},
+		Annotations: map[string]string{"deschedbench/run-id": runID},
+	}}
+}
+
+func TestRunDeletesClusterRBACForRun(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		managedClusterRole("role-a", "run-a"),
+		managedClusterRole("role-b", "run-b"),
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{
+			Name:        "binding-a",
+			Labels:      map[string]string{"deschedbench": "true"},
+			Annotations: map[string]string{"deschedbench/run-id": "run-a"},
+		}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}},
+	)
+	service := NewCleanupService(client, logging.GetLogger())
+	if err := service.Run(context.Background(), Scope{Namespace: "deschedbench-a", RunID: "run-a"}); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.RbacV1().ClusterRoles().Get(ctx, "role-a", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected role-a deleted")
+	}
+	if _, err := client.RbacV1().ClusterRoleBindings().Get(ctx, "binding-a", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected binding-a deleted")
+	}
+	for _, name := range []string{"role-b", "unmanaged"} {
+		if _, err := client.RbacV1().ClusterRoles().Get(ctx, name, metav1.GetOptions{}); err != nil {
+			t.Fatalf("expected %s to remain, got %v", name, err)
+		}
+	}
+}
+
+func TestRunDryRunChangesNothing(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-a"}},
+		managedClusterRole("role-a", "run-a"),
+		cordonedNode("node-1", "run-a"),
+	)
+	service := NewCleanupService(client, logging.GetLogger())
+	scope := Scope{NamespacePrefix: "deschedbench-", DryRun: true}
+	actions, err := service.Plan(context.Background(), scope)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	want := []string{"delete namespace/deschedbench-a", "delete clusterrole/role-a (run run-a)", "restore node/node-1 (run run-a)"}
+	if len(actions) != len(want) {
+		t.Fatalf("expected %d actions, got %v", len(want), actions)
+	}
+	for i, action := range actions {
+		if action.String() != want[i] {
+			t.Fatalf("action %d: expected %q, got %q", i, want[i], action)
+		}
+	}
+	if err := service.Run(context.Background(), scope); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+	if _, err := client.RbacV1().ClusterRoles().Get(context.Background(), "role-a", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected role-a to remain in dry run, got %v", err)
+	}
+}
+
+func TestRunDeletesPriorityClassesForRun(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		k8s.BuildPriorityClass("deschedbench-run-a-high", 100000, "run-a"),
+		k8s.BuildPriorityClass("deschedbench-run-b-high", 100000, "run-b"),
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged"}},
+	)
+	service := NewCleanupService(client, logging.GetLogger())
+	if err := service.Run(context.Background(), Scope{Namespace: "deschedbench-a", RunID: "run-a"}); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := client.SchedulingV1().PriorityClasses().Get(ctx, "deschedbench-run-a-high", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected the priority class of run-a deleted")
+	}
+	for _, name := range []string{"deschedbench-run-b-high", "unmanaged"} {
+		if _, err := client.SchedulingV1().PriorityClasses().Get(ctx, name, metav1.GetOptions{}); err != nil {
+			t.Fatalf("expected %s to remain, got %v", name, err)
+		}
+	}
+}
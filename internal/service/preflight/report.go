@@ -0,0 +1,75 @@
+package preflight
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+type CheckResult struct {
+	Name    string   `json:"name"`
+	Status  Status   `json:"status"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+func (r *Report) add(result CheckResult) {
+	r.Checks = append(r.Checks, result)
+	if severity(result.Status) > severity(r.Status) {
+		r.Status = result.Status
+	}
+}
+
+func (r Report) Failed() bool {
+	return r.Status == StatusFail
+}
+
+// FailedChecks returns the names of failing checks, for error messages.
+func (r Report) FailedChecks() []string {
+	var names []string
+	for _, check := range r.Checks {
+		if check.Status == StatusFail {
+			names = append(names, check.Name)
+		}
+	}
+	return names
+}
+
+func WriteText(w io.Writer, r Report) error {
+	for _, check := range r.Checks {
+		if _, err := fmt.Fprintf(w, "%-4s  %-20s %s\n", strings.ToUpper(string(check.Status)), check.Name, check.Message); err != nil {
+			return err
+		}
+		for _, detail := range check.Details {
+			if _, err := fmt.Fprintf(w, "      %-20s - %s\n", "", detail); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "preflight %s\n", r.Status)
+	return err
+}
+
+func severity(status Status) int {
+	switch status {
+	case StatusFail:
+		return 2
+	case StatusWarn:
+		return 1
+	default:
+		return 0
+	}
+}
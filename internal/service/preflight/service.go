@@ -0,0 +1,397 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+
+	"k8s-descheduler-benchmark/internal/capacity"
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/logging"
+	"k8s-descheduler-benchmark/internal/workloads"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options describes the run to check. Mix, SizeClasses and Noise come from
+// the benchmark plan, so the capacity check sees the same pods as the run.
+// DeschedulerImages are the images the run compares; the server version is
+// checked against the Kubernetes release each one targets.
+type Options struct {
+	Mix               workloads.Mix
+	SizeClasses       map[string]workloads.SizeClass
+	Noise             []workloads.WorkloadConfig
+	DeschedulerImages []string
+	Iterations        int
+	MetricsPort       int
+}
+
+type PreflightService struct {
+	client kubernetes.Interface
+	logger *slog.Logger
+}
+
+func NewPreflightService(client kubernetes.Interface, logger *slog.Logger) *PreflightService {
+	if logger == nil {
+		logger = logging.GetLogger()
+	}
+	return &PreflightService{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Run executes every check and returns the combined report. Individual check
+// failures are reported in the result rather than returned as errors.
+func (s *PreflightService) Run(ctx context.Context, opts Options) Report {
+	report := Report{Status: StatusPass}
+	report.add(s.checkServerVersion(opts.DeschedulerImages))
+	report.add(s.checkPermissions(ctx))
+
+	nodes, err := k8s.ListNodes(ctx, s.client, "")
+	if err != nil {
+		report.add(CheckResult{Name: "nodes", Status: StatusFail, Message: fmt.Sprintf("list nodes: %v", err)})
+	} else {
+		report.add(checkCordonedNodes(nodes))
+		report.add(checkWorkerCount(nodes, opts.Iterations))
+		report.add(s.checkCapacity(ctx, nodes, opts))
+	}
+
+	report.add(s.checkLeftoverNamespaces(ctx))
+	report.add(s.checkLeftoverClusterRoles(ctx))
+	report.add(checkMetricsPort(opts.MetricsPort))
+	return report
+}
+
+// checkServerVersion warns when the server is not the Kubernetes minor a
+// requested descheduler image is built against. Images whose tag is not a
+// descheduler release, such as a digest or "latest", are not checked.
+func (s *PreflightService) checkServerVersion(images []string) CheckResult {
+	result := CheckResult{Name: "server-version"}
+	info, err := s.client.Discovery().ServerVersion()
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("get server version: %v", err)
+		return result
+	}
+	result.Message = info.GitVersion
+	minor, err := strconv.Atoi(strings.TrimRight(info.Minor, "+"))
+	switch {
+	case err != nil:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%s (unable to parse minor version %q)", info.GitVersion, info.Minor)
+	case info.Major != "1":
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%s (expected Kubernetes v1)", info.GitVersion)
+	default:
+		var mismatches []string
+		for _, image := range images {
+			if target, ok := imageKubernetesMinor(image); ok && target != minor {
+				mismatches = append(mismatches, fmt.Sprintf("%s targets v1.%d", image, target))
+			}
+		}
+		result.Status = StatusPass
+		if len(mismatches) > 0 {
+			result.Status = StatusWarn
+			result.Message = fmt.Sprintf("%s (%s)", info.GitVersion, strings.Join(mismatches, "; "))
+		}
+	}
+	return result
+}
+
+// imageKubernetesMinor returns the Kubernetes minor a descheduler image is
+// built against: descheduler v0.N.x tracks Kubernetes v1.N.
+func imageKubernetesMinor(image string) (int, bool) {
+	image, _, _ = strings.Cut(image, "@")
+	idx := strings.LastIndex(image, ":")
+	if idx < 0 || strings.Contains(image[idx:], "/") {
+		return 0, false
+	}
+	parts := strings.Split(image[idx+1:], ".")
+	if len(parts) < 2 || parts[0] != "v0" {
+		return 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+	return minor, true
+}
+
+type permission struct {
+	Group       string
+	Resource    string
+	Subresource string
+	Verbs       []string
+}
+
+// requiredPermissions lists every API call a benchmark run makes, including
+// creating the descheduler RBAC objects and deleting them at cleanup.
+var requiredPermissions = []permission{
+	{Resource: "namespaces", Verbs: []string{"get", "list", "create", "delete"}},
+	{Resource: "nodes", Verbs: []string{"get", "list", "update"}},
+	{Resource: "pods", Verbs: []string{"get", "list", "create"}},
+	{Resource: "pods", Subresource: "eviction", Verbs: []string{"create"}},
+	{Resource: "pods", Subresource: "log", Verbs: []string{"get"}},
+	{Resource: "pods", Subresource: "proxy", Verbs: []string{"get"}},
+	{Resource: "services", Subresource: "proxy", Verbs: []string{"get"}},
+	{Resource: "events", Verbs: []string{"list"}},
+	{Resource: "serviceaccounts", Verbs: []string{"get", "create", "update"}},
+	{Resource: "configmaps", Verbs: []string{"get", "create", "update"}},
+	{Resource: "services", Verbs: []string{"get", "create", "update"}},
+	{Group: "apps", Resource: "deployments", Verbs: []string{"get", "create", "update"}},
+	{Group: "apps", Resource: "statefulsets", Verbs: []string{"get", "create", "update"}},
+	{Group: "apps", Resource: "replicasets", Verbs: []string{"get", "create", "update"}},
+	{Group: "batch", Resource: "jobs", Verbs: []string{"get", "list", "create", "update"}},
+	{Group: "batch", Resource: "cronjobs", Verbs: []string{"get", "create", "update"}},
+	{Group: "scheduling.k8s.io", Resource: "priorityclasses", Verbs: []string{"list", "create", "delete"}},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Verbs: []string{"get", "list", "create", "update", "delete"}},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings", Verbs: []string{"get", "list", "create", "update", "delete"}},
+}
+
+func (s *PreflightService) checkPermissions(ctx context.Context) CheckResult {
+	result := CheckResult{Name: "rbac"}
+	var denied []string
+	total := 0
+	for _, perm := range requiredPermissions {
+		for _, verb := range perm.Verbs {
+			total++
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Group:       perm.Group,
+						Resource:    perm.Resource,
+						Subresource: perm.Subresource,
+						Verb:        verb,
+					},
+				},
+			}
+			resp, err := s.client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+			if err != nil {
+				result.Status = StatusFail
+				result.Message = fmt.Sprintf("access review failed: %v", err)
+				return result
+			}
+			if !resp.Status.Allowed {
+				denied = append(denied, describePermission(perm, verb))
+			}
+		}
+	}
+	if len(denied) > 0 {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("%d of %d required permissions denied", len(denied), total)
+		result.Details = denied
+		return result
+	}
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%d required permissions allowed", total)
+	return result
+}
+
+func describePermission(perm permission, verb string) string {
+	resource := perm.Resource
+	if perm.Subresource != "" {
+		resource += "/" + perm.Subresource
+	}
+	if perm.Group != "" {
+		resource += "." + perm.Group
+	}
+	return verb + " " + resource
+}
+
+// checkCordonedNodes fails on workers a previous deschedbench run left
+// cordoned and warns on workers cordoned by someone else, which cleanup will
+// not touch.
+func checkCordonedNodes(nodes []corev1.Node) CheckResult {
+	result := CheckResult{Name: "cordoned-nodes"}
+	var ours, others []string
+	for _, node := range nodes {
+		if !node.Spec.Unschedulable || k8s.IsControlPlaneNode(node.Labels) {
+			continue
+		}
+		if runID, ok := k8s.CordonedBy(node); ok {
+			ours = append(ours, node.Name)
+			result.Details = append(result.Details, fmt.Sprintf("%s: cordoned by deschedbench run %s", node.Name, runID))
+			continue
+		}
+		others = append(others, node.Name)
+		result.Details = append(result.Details, node.Name+": cordoned outside deschedbench")
+	}
+	switch {
+	case len(ours) > 0:
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("workers left cordoned by deschedbench: %s. Run `make cleanup`", strings.Join(ours, ", "))
+	case len(others) > 0:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("workers cordoned by others, excluded from the benchmark: %s", strings.Join(others, ", "))
+	default:
+		result.Status = StatusPass
+		result.Message = "no cordoned workers"
+	}
+	return result
+}
+
+func checkWorkerCount(nodes []corev1.Node, iterations int) CheckResult {
+	result := CheckResult{Name: "worker-count"}
+	if iterations <= 0 {
+		iterations = 1
+	}
+	workers := len(schedulableWorkers(nodes))
+	// Each iteration drains a distinct worker and the pods need somewhere to go.
+	required := iterations + 1
+	if workers < required {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("%d schedulable workers, need at least %d for %d iterations", workers, required, iterations)
+		return result
+	}
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%d schedulable workers for %d iterations", workers, iterations)
+	return result
+}
+
+// checkCapacity verifies that all benchmark pods fit on the workers left after
+// each planned drain. Requests of pods already running count against
+// allocatable, and noise pods are placed first, as the run creates them
+// first.
+func (s *PreflightService) checkCapacity(ctx context.Context, nodes []corev1.Node, opts Options) CheckResult {
+	result := CheckResult{Name: "capacity"}
+	if workloads.MixTotal(opts.Mix) <= 0 {
+		result.Status = StatusWarn
+		result.Message = "no pod count given, skipped"
+		return result
+	}
+	shapes, err := capacity.Shapes(opts.Mix, opts.SizeClasses)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = err.Error()
+		return result
+	}
+	pods, err := s.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("list pods: %v", err)
+		return result
+	}
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+	free, unplaced, err := capacity.ReserveNoise(capacity.FreeNodes(nodes, pods.Items, ""), opts.Noise)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = err.Error()
+		return result
+	}
+	if unplaced > 0 {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("%d noise pods do not fit the workers", unplaced)
+		return result
+	}
+	plan := capacity.PlanDrains(free, capacity.DrainOrder(free, iterations, 1), shapes)
+	result.Details = strings.Split(capacity.Format(plan), "\n")
+	if !plan.Feasible {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("%d pods do not fit every planned drain (max %d)", plan.PodsTotal, plan.MaxPodsTotal)
+		return result
+	}
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%d pods fit every planned drain", plan.PodsTotal)
+	return result
+}
+
+func (s *PreflightService) checkLeftoverNamespaces(ctx context.Context) CheckResult {
+	result := CheckResult{Name: "leftover-namespaces"}
+	namespaces, err := s.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("list namespaces: %v", err)
+		return result
+	}
+	for _, ns := range namespaces.Items {
+		if strings.HasPrefix(ns.Name, "deschedbench-") {
+			result.Details = append(result.Details, ns.Name)
+		}
+	}
+	if len(result.Details) > 0 {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%d deschedbench namespaces left behind. Run `make cleanup`", len(result.Details))
+		return result
+	}
+	result.Status = StatusPass
+	result.Message = "none"
+	return result
+}
+
+func (s *PreflightService) checkLeftoverClusterRoles(ctx context.Context) CheckResult {
+	result := CheckResult{Name: "leftover-rbac"}
+	roles, err := s.client.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{LabelSelector: k8s.ManagedSelector})
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("list cluster roles: %v", err)
+		return result
+	}
+	for _, role := range roles.Items {
+		result.Details = append(result.Details, describeLeftover("clusterrole", &role))
+	}
+	bindings, err := s.client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: k8s.ManagedSelector})
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("list cluster role bindings: %v", err)
+		return result
+	}
+	for _, binding := range bindings.Items {
+		result.Details = append(result.Details, describeLeftover("clusterrolebinding", &binding))
+	}
+	if len(result.Details) > 0 {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%d cluster-scoped deschedbench objects left behind. Run `make cleanup`", len(result.Details))
+		return result
+	}
+	result.Status = StatusPass
+	result.Message = "none"
+	return result
+}
+
+func describeLeftover(kind string, obj metav1.Object) string {
+	out := kind + "/" + obj.GetName()
+	if runID := k8s.RunIDOf(obj); runID != "" {
+		out += " (run " + runID + ")"
+	}
+	return out
+}
+
+func checkMetricsPort(port int) CheckResult {
+	result := CheckResult{Name: "metrics-port"}
+	if port <= 0 {
+		result.Status = StatusWarn
+		result.Message = "no metrics port configured"
+		return result
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("port %d unavailable: %v", port, err)
+		return result
+	}
+	listener.Close()
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("port %d free", port)
+	return result
+}
+
+func schedulableWorkers(nodes []corev1.Node) []corev1.Node {
+	out := make([]corev1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || k8s.IsControlPlaneNode(node.Labels) {
+			continue
+		}
+		out = append(out, node)
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"k8s-descheduler-benchmark/internal/logging"
+	"k8s-descheduler-benchmark/internal/workloads"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var smallClass = map[string]workloads.SizeClass{"small": {Name: "small", CPU: "100m", Memory: "128Mi"}}
+
+func worker(name, cpu, mem string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(mem),
+			corev1.ResourcePods:   resource.MustParse("110"),
+		}},
+	}
+}
+
+func newClient(denied string, objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{Major: "1", Minor: "32", GitVersion: "v1.32.0"}
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = attrs.Resource+"/"+attrs.Verb != denied
+		return true, review, nil
+	})
+	return client
+}
+
+func findCheck(t *testing.T, report Report, name string) CheckResult {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("check %s missing", name)
+	return CheckResult{}
+}
+
+func TestRunPassesOnHealthyCluster(t *testing.T) {
+	client := newClient("",
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cp", Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""}}},
+		worker("w1", "2", "4Gi"),
+		worker("w2", "2", "4Gi"),
+		worker("w3", "2", "4Gi"),
+	)
+	svc := NewPreflightService(client, logging.GetLogger())
+	report := svc.Run(context.Background(), Options{Mix: workloads.Mix{"small": 20}, SizeClasses: smallClass, Iterations: 2})
+	if report.Failed() {
+		var buf bytes.Buffer
+		_ = WriteText(&buf, report)
+		t.Fatalf("expected preflight to pass:\n%s", buf.String())
+	}
+	if got := findCheck(t, report, "server-version"); got.Status != StatusPass {
+		t.Fatalf("unexpected server-version status: %+v", got)
+	}
+}
+
+func TestRunReportsFailuresAndWarnings(t *testing.T) {
+	client := newClient("nodes/update",
+		worker("w1", "1", "1Gi"),
+		worker("w2", "1", "1Gi"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-old"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-descheduler", Labels: map[string]string{"deschedbench": "true"}}},
+	)
+	svc := NewPreflightService(client, logging.GetLogger())
+	report := svc.Run(context.Background(), Options{Mix: workloads.Mix{"small": 20}, SizeClasses: smallClass, Iterations: 2})
+	if !report.Failed() {
+		t.Fatalf("expected preflight to fail")
+	}
+	rbac := findCheck(t, report, "rbac")
+	if rbac.Status != StatusFail || len(rbac.Details) != 1 || rbac.Details[0] != "update nodes" {
+		t.Fatalf("unexpected rbac result: %+v", rbac)
+	}
+	if got := findCheck(t, report, "worker-count"); got.Status != StatusFail {
+		t.Fatalf("expected worker-count failure, got %+v", got)
+	}
+	if got := findCheck(t, report, "capacity"); got.Status != StatusFail {
+		t.Fatalf("expected capacity failure, got %+v", got)
+	}
+	if got := findCheck(t, report, "leftover-namespaces"); got.Status != StatusWarn {
+		t.Fatalf("expected leftover-namespaces warning, got %+v", got)
+	}
+	if got := findCheck(t, report, "leftover-rbac"); got.Status != StatusWarn {
+		t.Fatalf("expected leftover-rbac warning, got %+v", got)
+	}
+	names := strings.Join(report.FailedChecks(), ",")
+	if !strings.Contains(names, "rbac") || !strings.Contains(names, "capacity") {
+		t.Fatalf("unexpected failed checks: %s", names)
+	}
+}
+
+func TestCheckServerVersionFollowsImages(t *testing.T) {
+	svc := NewPreflightService(newClient(""), logging.GetLogger())
+	if got := svc.checkServerVersion([]string{"registry.k8s.io/descheduler/descheduler:v0.32.2", "localhost:5000/descheduler@sha256:abc"}); got.Status != StatusPass {
+		t.Fatalf("expected v0.32 to match a v1.32 server, got %+v", got)
+	}
+	got := svc.checkServerVersion([]string{"registry.k8s.io/descheduler/descheduler:v0.31.0", "registry.k8s.io/descheduler/descheduler:v0.32.2"})
+	if got.Status != StatusWarn || !strings.Contains(got.Message, "v0.31.0 targets v1.31") || strings.Contains(got.Message, "v0.32.2") {
+		t.Fatalf("expected a warning for the v0.31 image only, got %+v", got)
+	}
+}
+
+func TestCheckCordonedNodes(t *testing.T) {
+	other := *worker("w1", "1", "1Gi")
+	other.Spec.Unschedulable = true
+	if got := checkCordonedNodes([]corev1.Node{other}); got.Status != StatusWarn {
+		t.Fatalf("expected warning for node cordoned by others, got %+v", got)
+	}
+	ours := *worker("w2", "1", "1Gi")
+	ours.Spec.Unschedulable = true
+	ours.Annotations = map[string]string{"deschedbench/cordoned-by": "20260209-000000"}
+	got := checkCordonedNodes([]corev1.Node{other, ours})
+	if got.Status != StatusFail || len(got.Details) != 2 {
+		t.Fatalf("expected failure for node cordoned by deschedbench, got %+v", got)
+	}
+}
+
+func TestCheckCapacityCountsMixAndNoise(t *testing.T) {
+	nodes := []corev1.Node{*worker("w1", "2", "4Gi"), *worker("w2", "2", "4Gi"), *worker("w3", "2", "4Gi")}
+	svc := NewPreflightService(newClient(""), logging.GetLogger())
+	sizes := workloads.DefaultSizeClasses()
+	opts := Options{Mix: workloads.Mix{"small": 20}, SizeClasses: sizes, Iterations: 2}
+	if got := svc.checkCapacity(context.Background(), nodes, opts); got.Status != StatusPass {
+		t.Fatalf("expected the small mix to fit, got %+v", got)
+	}
+
+	large := opts
+	large.Mix = workloads.Mix{"small": 20, "statefulset/large": 6}
+	if got := svc.checkCapacity(context.Background(), nodes, large); got.Status != StatusFail {
+		t.Fatalf("expected the large pods of the mix to be counted, got %+v", got)
+	}
+
+	noisy := opts
+	noisy.Noise = []workloads.WorkloadConfig{{Mix: workloads.Mix{"large": 8}, SizeClasses: sizes}}
+	if got := svc.checkCapacity(context.Background(), nodes, noisy); got.Status != StatusFail {
+		t.Fatalf("expected noise pods to take capacity first, got %+v", got)
+	}
+}
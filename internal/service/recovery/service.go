@@ -0,0 +1,246 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s-descheduler-benchmark/internal/journal"
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/logging"
+	"k8s-descheduler-benchmark/internal/report"
+	"k8s-descheduler-benchmark/internal/service/cleanup"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type Options struct {
+	Dir    string
+	RunID  string
+	Wait   bool
+	DryRun bool
+}
+
+// Outcome describes what recovery did (or would do) for one interrupted run.
+type Outcome struct {
+	RunID       string           `json:"run_id"`
+	Namespace   string           `json:"namespace"`
+	Phase       string           `json:"phase"`
+	Actions     []cleanup.Action `json:"actions"`
+	Warnings    []string         `json:"warnings,omitempty"`
+	PartialPath string           `json:"partial_path,omitempty"`
+}
+
+type RecoveryService struct {
+	client  kubernetes.Interface
+	logger  *slog.Logger
+	cleanup *cleanup.CleanupService
+}
+
+func NewRecoveryService(client kubernetes.Interface, logger *slog.Logger) *RecoveryService {
+	if logger == nil {
+		logger = logging.GetLogger()
+	}
+	return &RecoveryService{
+		client:  client,
+		logger:  logger,
+		cleanup: cleanup.NewCleanupService(client, logger),
+	}
+}
+
+// Run restores the cluster for every journal still marked running (or only
+// opts.RunID) and writes a partial result file next to the planned output.
+func (s *RecoveryService) Run(ctx context.Context, opts Options) ([]Outcome, error) {
+	dir := opts.Dir
+	if dir == "" {
+		dir = journal.DefaultDir
+	}
+	journals, err := journal.List(dir)
+	if err != nil {
+		return nil, err
+	}
+	var outcomes []Outcome
+	for _, j := range journals {
+		if opts.RunID != "" && j.RunID != opts.RunID {
+			continue
+		}
+		if opts.RunID == "" && j.Status != journal.StatusRunning {
+			continue
+		}
+		outcome, err := s.recover(ctx, dir, j, opts)
+		if err != nil {
+			return outcomes, fmt.Errorf("recover run %s: %w", j.RunID, err)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	if opts.RunID != "" && len(outcomes) == 0 {
+		return nil, fmt.Errorf("no journal for run %s in %s", opts.RunID, dir)
+	}
+	return outcomes, nil
+}
+
+func (s *RecoveryService) recover(ctx context.Context, dir string, j journal.Journal, opts Options) (Outcome, error) {
+	scope := cleanup.Scope{
+		Namespace: j.Namespace,
+		RunID:     j.RunID,
+		Wait:      opts.Wait,
+		DryRun:    opts.DryRun,
+	}
+	actions, err := s.cleanup.Plan(ctx, scope)
+	if err != nil {
+		return Outcome{}, err
+	}
+	actions, warnings, err := s.reconcile(ctx, j, actions)
+	if err != nil {
+		return Outcome{}, err
+	}
+	outcome := Outcome{RunID: j.RunID, Namespace: j.Namespace, Phase: j.Phase, Actions: actions, Warnings: warnings}
+	for _, warning := range warnings {
+		s.logger.Warn("recover run", logging.StringField("run_id", j.RunID), logging.StringField("warning", warning))
+	}
+	if opts.DryRun {
+		return outcome, nil
+	}
+
+	s.logger.Info("recover run",
+		logging.StringField("run_id", j.RunID),
+		logging.StringField("phase", j.Phase),
+	)
+	if err := s.cleanup.Apply(ctx, actions, opts.Wait); err != nil {
+		return outcome, err
+	}
+	if j.OutputPath != "" {
+		outcome.PartialPath = PartialPath(j.OutputPath)
+		if err := report.WriteResult(outcome.PartialPath, NewPartial(j)); err != nil {
+			return outcome, err
+		}
+	}
+	j.Status = journal.StatusRecovered
+	if err := journal.Save(dir, j); err != nil {
+		return outcome, err
+	}
+	return outcome, nil
+}
+
+// actionOrder keeps namespaces first, so the descheduler stops before its
+// RBAC goes and no pod uses a PriorityClass when it is deleted.
+var actionOrder = map[string]int{"namespace": 0, "clusterrolebinding": 1, "clusterrole": 2, "priorityclass": 3, "node": 4}
+
+// reconcile checks the annotation scan of cleanup against the journal. An
+// object the journal lists that the scan missed, such as one whose labels
+// were never written, is deleted too: its name is unique to the run. A
+// journaled node the scan missed is uncordoned while it is unschedulable
+// without another run's annotation, since runs only drain schedulable
+// nodes. A node another run cordoned is left alone and reported.
+func (s *RecoveryService) reconcile(ctx context.Context, j journal.Journal, actions []cleanup.Action) ([]cleanup.Action, []string, error) {
+	planned := make(map[string]bool, len(actions))
+	for _, action := range actions {
+		planned[action.Kind+"/"+action.Name] = true
+	}
+	for _, obj := range j.Objects {
+		if planned[obj.Kind+"/"+obj.Name] {
+			continue
+		}
+		exists, err := s.exists(ctx, obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		if exists {
+			actions = append(actions, cleanup.Action{Verb: cleanup.VerbDelete, Kind: obj.Kind, Name: obj.Name, RunID: j.RunID})
+		}
+	}
+	var warnings []string
+	for _, name := range j.CordonedNodes {
+		if planned["node/"+name] {
+			continue
+		}
+		node, err := s.client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if !node.Spec.Unschedulable {
+			continue
+		}
+		if runID, ok := k8s.CordonedBy(*node); ok && runID != j.RunID {
+			warnings = append(warnings, fmt.Sprintf("node %s is cordoned by run %s; left alone", name, runID))
+			continue
+		}
+		actions = append(actions, cleanup.Action{Verb: cleanup.VerbUncordon, Kind: "node", Name: name, RunID: j.RunID})
+	}
+	sort.SliceStable(actions, func(a, b int) bool {
+		return actionOrder[actions[a].Kind] < actionOrder[actions[b].Kind]
+	})
+	return actions, warnings, nil
+}
+
+// exists reports whether the journaled object is still in the cluster.
+func (s *RecoveryService) exists(ctx context.Context, obj journal.Object) (bool, error) {
+	var err error
+	switch obj.Kind {
+	case "namespace":
+		_, err = s.client.CoreV1().Namespaces().Get(ctx, obj.Name, metav1.GetOptions{})
+	case "priorityclass":
+		_, err = s.client.SchedulingV1().PriorityClasses().Get(ctx, obj.Name, metav1.GetOptions{})
+	case "clusterrole":
+		_, err = s.client.RbacV1().ClusterRoles().Get(ctx, obj.Name, metav1.GetOptions{})
+	case "clusterrolebinding":
+		_, err = s.client.RbacV1().ClusterRoleBindings().Get(ctx, obj.Name, metav1.GetOptions{})
+	default:
+		return false, fmt.Errorf("unsupported journal object %s/%s", obj.Kind, obj.Name)
+	}
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NewPartial builds the result file of an interrupted run from its journal:
+// status interrupted, failed_phase the last phase mark, and the phases and
+// samples recorded up to then. The summary compares the first sample with
+// the last one, and the run counts as not rebalanced.
+func NewPartial(j journal.Journal) report.Result {
+	config := j.Config
+	if config.RunID == "" {
+		// Journals written before the config was recorded.
+		config.RunID = j.RunID
+		config.Scenario = j.Scenario
+		config.Profile = j.Profile
+		config.Namespace = j.Namespace
+		config.StartTime = j.StartTime
+	}
+	result := report.Result{
+		Status:      report.StatusInterrupted,
+		Error:       fmt.Sprintf("run interrupted after %s", j.Phase),
+		FailedPhase: j.Phase,
+		Config:      config,
+		Phases:      j.Phases,
+		Samples:     j.Samples,
+		Summary: report.Summary{
+			RunID:                j.RunID,
+			Scenario:             j.Scenario,
+			Profile:              j.Profile,
+			DurationSeconds:      j.UpdatedAt.Sub(j.StartTime).Seconds(),
+			RebalanceTimeSeconds: -1,
+		},
+	}
+	if n := len(j.Samples); n > 0 {
+		result.Summary.Before = j.Samples[0]
+		result.Summary.After = j.Samples[n-1]
+	}
+	return result
+}
+
+// PartialPath derives results/descheduler-partial.json from
+// results/descheduler.json.
+func PartialPath(outputPath string) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + "-partial" + ext
+}
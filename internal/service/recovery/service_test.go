@@ -0,0 +1,157 @@
+package recovery
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/journal"
+	"k8s-descheduler-benchmark/internal/logging"
+	"k8s-descheduler-benchmark/internal/metrics"
+	"k8s-descheduler-benchmark/internal/report"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRunRestoresInterruptedRun(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "descheduler.json")
+	err := journal.Save(filepath.Join(dir, ".journal"), journal.Journal{
+		RunID:      "run-a",
+		Namespace:  "deschedbench-run-a",
+		OutputPath: output,
+		Status:     journal.StatusRunning,
+		StartTime:  time.Now(),
+		Phase:      "drain:start",
+		Samples:    []metrics.Sample{{PodsStddev: 3}, {PodsStddev: 1}},
+		Config:     report.RunConfig{RunID: "run-a", Profile: "low-node-utilization", PodsTotal: 20},
+	})
+	if err != nil {
+		t.Fatalf("save journal: %v", err)
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-run-a"}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "w1", Annotations: map[string]string{
+				"deschedbench/cordoned-by":            "run-a",
+				"deschedbench/previous-unschedulable": "false",
+			}},
+			Spec: corev1.NodeSpec{Unschedulable: true},
+		},
+	)
+	svc := NewRecoveryService(client, logging.GetLogger())
+	outcomes, err := svc.Run(context.Background(), Options{Dir: filepath.Join(dir, ".journal")})
+	if err != nil {
+		t.Fatalf("recover failed: %v", err)
+	}
+	if len(outcomes) != 1 || len(outcomes[0].Actions) != 2 {
+		t.Fatalf("unexpected outcomes: %+v", outcomes)
+	}
+	node, _ := client.CoreV1().Nodes().Get(context.Background(), "w1", metav1.GetOptions{})
+	if node.Spec.Unschedulable {
+		t.Fatalf("expected w1 restored")
+	}
+	partial, err := report.LoadResult(filepath.Join(dir, "descheduler-partial.json"))
+	if err != nil {
+		t.Fatalf("expected partial results: %v", err)
+	}
+	if partial.Status != report.StatusInterrupted || partial.FailedPhase != "drain:start" || partial.Config.PodsTotal != 20 {
+		t.Fatalf("unexpected partial result: status %s, phase %s, config %+v", partial.Status, partial.FailedPhase, partial.Config)
+	}
+	if partial.Summary.Before.PodsStddev != 3 || partial.Summary.After.PodsStddev != 1 || partial.Summary.RebalanceTimeSeconds != -1 {
+		t.Fatalf("unexpected partial summary: %+v", partial.Summary)
+	}
+	j, err := journal.Load(journal.Path(filepath.Join(dir, ".journal"), "run-a"))
+	if err != nil || j.Status != journal.StatusRecovered {
+		t.Fatalf("expected journal marked recovered, got %+v (%v)", j, err)
+	}
+
+	outcomes, err = svc.Run(context.Background(), Options{Dir: filepath.Join(dir, ".journal")})
+	if err != nil || len(outcomes) != 0 {
+		t.Fatalf("expected nothing left to recover, got %+v (%v)", outcomes, err)
+	}
+}
+
+func TestRunReconcilesJournalWithAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	err := journal.Save(dir, journal.Journal{
+		RunID:         "run-a",
+		Namespace:     "deschedbench-run-a",
+		Status:        journal.StatusRunning,
+		Phase:         "drain:start",
+		CordonedNodes: []string{"w1", "w2", "w3"},
+		Objects: []journal.Object{
+			{Kind: "namespace", Name: "deschedbench-run-a"},
+			{Kind: "namespace", Name: "deschedbench-run-a-noise-1"},
+			{Kind: "priorityclass", Name: "deschedbench-run-a-low"},
+			{Kind: "clusterrole", Name: "deschedbench-descheduler-run-a"},
+			{Kind: "clusterrolebinding", Name: "deschedbench-descheduler-run-a"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("save journal: %v", err)
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-run-a"}},
+		// Created without the labels the annotation scan looks for.
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-run-a-low"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "deschedbench-descheduler-run-a"}},
+		// w1 carries the run annotation, w2 lost it and w3 was taken over
+		// by another run.
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "w1", Annotations: map[string]string{
+				"deschedbench/cordoned-by":            "run-a",
+				"deschedbench/previous-unschedulable": "false",
+			}},
+			Spec: corev1.NodeSpec{Unschedulable: true},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "w2"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "w3", Annotations: map[string]string{
+				"deschedbench/cordoned-by":            "run-b",
+				"deschedbench/previous-unschedulable": "false",
+			}},
+			Spec: corev1.NodeSpec{Unschedulable: true},
+		},
+	)
+	svc := NewRecoveryService(client, logging.GetLogger())
+	outcomes, err := svc.Run(context.Background(), Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("recover failed: %v", err)
+	}
+	if len(outcomes) != 1 {
+		t.Fatalf("unexpected outcomes: %+v", outcomes)
+	}
+	var got []string
+	for _, action := range outcomes[0].Actions {
+		got = append(got, action.Verb+" "+action.Kind+"/"+action.Name)
+	}
+	want := []string{
+		"delete namespace/deschedbench-run-a",
+		"delete clusterrole/deschedbench-descheduler-run-a",
+		"delete priorityclass/deschedbench-run-a-low",
+		"restore node/w1",
+		"uncordon node/w2",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected actions:\n got %v\nwant %v", got, want)
+	}
+	if len(outcomes[0].Warnings) != 1 || !strings.Contains(outcomes[0].Warnings[0], "w3") {
+		t.Fatalf("expected a warning for w3, got %v", outcomes[0].Warnings)
+	}
+	if _, err := client.SchedulingV1().PriorityClasses().Get(context.Background(), "deschedbench-run-a-low", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected the journaled priority class deleted")
+	}
+	for name, unschedulable := range map[string]bool{"w1": false, "w2": false, "w3": true} {
+		node, _ := client.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		if node.Spec.Unschedulable != unschedulable {
+			t.Fatalf("expected %s unschedulable=%v", name, unschedulable)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"k8s-descheduler-benchmark/internal/report"
+)
+
+// Metrics regress compares. Higher is worse for each of them.
+const (
+	MetricBalance   = "after_pods_stddev"
+	MetricRebalance = "rebalance_time_seconds"
+	MetricEvictions = "evictions"
+)
+
+// ErrRegression is returned, wrapped, when the latest run regressed.
+var ErrRegression = errors.New("regression detected")
+
+// RegressOptions tune the regression check.
+type RegressOptions struct {
+	// Window is how many previous runs form the baseline.
+	Window int
+	// MinHistory is the fewest previous values a metric needs to be judged.
+	MinHistory int
+	// ZScore is how many standard deviations above the baseline mean the
+	// latest value must be to count as significant.
+	ZScore float64
+	// MinChange is the smallest relative increase over the mean that counts,
+	// so tiny but consistent differences are not flagged.
+	MinChange float64
+}
+
+// DefaultRegressOptions flag a metric 2 standard deviations and 10% above
+// the mean of the last 10 runs, given at least 3 of them.
+func DefaultRegressOptions() RegressOptions {
+	return RegressOptions{Window: 10, MinHistory: 3, ZScore: 2, MinChange: 0.1}
+}
+
+// MetricCheck is the verdict for one metric.
+type MetricCheck struct {
+	Metric    string  `json:"metric"`
+	Latest    float64 `json:"latest"`
+	Mean      float64 `json:"mean"`
+	Stddev    float64 `json:"stddev"`
+	Samples   int     `json:"samples"`
+	Z         float64 `json:"z"`
+	Change    float64 `json:"change"`
+	Regressed bool    `json:"regressed"`
+	Note      string  `json:"note,omitempty"`
+}
+
+// Regression compares one run against the runs before it with the same
+// configuration.
+type Regression struct {
+	Latest  Entry         `json:"latest"`
+	History []string      `json:"history"`
+	Checks  []MetricCheck `json:"checks"`
+}
+
+// Regressed reports whether any metric regressed.
+func (r Regression) Regressed() bool {
+	for _, c := range r.Checks {
+		if c.Regressed {
+			return true
+		}
+	}
+	return false
+}
+
+// Baseline picks the last window successful runs before latest with the
+// same configuration key.
+func Baseline(entries []Entry, latest Entry, window int) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if e.RunID == latest.RunID || e.Config != latest.Config || e.Status != report.StatusSuccess {
+			continue
+		}
+		if e.StartTime.After(latest.StartTime) {
+			continue
+		}
+		out = append(out, e)
+	}
+	if window > 0 && len(out) > window {
+		out = out[len(out)-window:]
+	}
+	return out
+}
+
+// Regress checks balance, rebalance time and evictions of latest against
+// history. A metric regresses when it is both ZScore standard deviations
+// and MinChange above the history mean. A negative rebalance time means
+// the run never rebalanced; it is skipped in the history and counts as a
+// regression in the latest run.
+func Regress(latest Entry, history []Entry, opts RegressOptions) Regression {
+	out := Regression{Latest: latest}
+	for _, e := range history {
+		out.History = append(out.History, e.RunID)
+	}
+	metrics := []struct {
+		name  string
+		value func(Entry) (float64, bool)
+	}{
+		{MetricBalance, func(e Entry) (float64, bool) { return e.AfterPodsStddev, true }},
+		{MetricRebalance, func(e Entry) (float64, bool) { return e.RebalanceTimeSeconds, e.RebalanceTimeSeconds >= 0 }},
+		{MetricEvictions, func(e Entry) (float64, bool) { return float64(e.Evictions), true }},
+	}
+	for _, m := range metrics {
+		var values []float64
+		for _, e := range history {
+			if v, ok := m.value(e); ok {
+				values = append(values, v)
+			}
+		}
+		latestValue, ok := m.value(latest)
+		out.Checks = append(out.Checks, checkMetric(m.name, latestValue, ok, values, opts))
+	}
+	return out
+}
+
+func checkMetric(name string, latest float64, ok bool, values []float64, opts RegressOptions) MetricCheck {
+	check := MetricCheck{Metric: name, Latest: latest, Samples: len(values)}
+	if len(values) < opts.MinHistory {
+		check.Note = fmt.Sprintf("only %d previous values, need %d", len(values), opts.MinHistory)
+		return check
+	}
+	check.Mean, check.Stddev = meanStddev(values)
+	if !ok {
+		check.Regressed = true
+		check.Note = "did not rebalance"
+		return check
+	}
+	increase := latest - check.Mean
+	if check.Mean != 0 {
+		check.Change = increase / check.Mean
+	}
+	switch {
+	case check.Stddev > 0:
+		check.Z = increase / check.Stddev
+		check.Regressed = check.Z >= opts.ZScore && (check.Mean == 0 || check.Change >= opts.MinChange)
+	case increase > 0:
+		// Identical history: any increase beyond MinChange stands out.
+		check.Regressed = check.Mean == 0 || check.Change >= opts.MinChange
+		check.Note = "no variance in history"
+	}
+	return check
+}
+
+func meanStddev(values []float64) (float64, float64) {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	sq := 0.0
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)-1))
+}
+
+// WriteRegressionText prints one line per metric.
+func WriteRegressionText(w io.Writer, r Regression) error {
+	if _, err := fmt.Fprintf(w, "run %s (%s, %s, config %s) vs %d previous runs\n",
+		r.Latest.RunID, r.Latest.Profile, r.Latest.DeschedulerImage, r.Latest.Config, len(r.History)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%-24s %10s %10s %10s %8s %8s\n", "METRIC", "LATEST", "MEAN", "STDDEV", "Z", "CHANGE"); err != nil {
+		return err
+	}
+	for _, c := range r.Checks {
+		verdict := "ok"
+		if c.Regressed {
+			verdict = "REGRESSION"
+		}
+		if c.Note != "" {
+			verdict += " (" + c.Note + ")"
+		}
+		if _, err := fmt.Fprintf(w, "%-24s %10.2f %10.2f %10.2f %8.2f %+7.1f%% %s\n",
+			c.Metric, c.Latest, c.Mean, c.Stddev, c.Z, c.Change*100, verdict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteHistoryText prints one line per entry.
+func WriteHistoryText(w io.Writer, entries []Entry) error {
+	if _, err := fmt.Fprintf(w, "%-16s %-20s %-30s %-12s %-12s %-11s %9s %9s %9s  %s\n",
+		"RUN", "START", "PROFILE", "CLUSTER", "CONFIG", "STATUS", "STDDEV", "REBAL", "EVICT", "IMAGE"); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "%-16s %-20s %-30s %-12s %-12s %-11s %9.2f %8.1fs %9d  %s\n",
+			e.RunID, e.StartTime.Local().Format("2006-01-02 15:04:05"), e.Profile, e.Cluster, e.Config, e.Status,
+			e.AfterPodsStddev, e.RebalanceTimeSeconds, e.Evictions, e.DeschedulerImage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
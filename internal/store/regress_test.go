@@ -0,0 +1,72 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/report"
+)
+
+func regressHistory(stddevs ...float64) []Entry {
+	t0 := time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC)
+	var out []Entry
+	for i, v := range stddevs {
+		out = append(out, Entry{
+			RunID:                string(rune('a' + i)),
+			StartTime:            t0.Add(time.Duration(i) * time.Hour),
+			Config:               "cfg",
+			Status:               report.StatusSuccess,
+			AfterPodsStddev:      v,
+			RebalanceTimeSeconds: 30,
+			Evictions:            10,
+		})
+	}
+	return out
+}
+
+func TestBaseline(t *testing.T) {
+	entries := regressHistory(1, 1, 1, 1)
+	entries[1].Status = report.StatusFailed
+	entries[2].Config = "other"
+	latest := entries[3]
+	got := Baseline(entries, latest, 10)
+	if len(got) != 1 || got[0].RunID != "a" {
+		t.Fatalf("expected only the earlier successful run with the same config, got %+v", got)
+	}
+	if got := Baseline(regressHistory(1, 1, 1, 1), latest, 2); len(got) != 2 || got[0].RunID != "b" {
+		t.Fatalf("expected the window to keep the most recent runs, got %+v", got)
+	}
+}
+
+func TestRegress(t *testing.T) {
+	history := regressHistory(1.0, 1.1, 0.9, 1.0, 1.05)
+	latest := Entry{RunID: "z", AfterPodsStddev: 1.6, RebalanceTimeSeconds: -1, Evictions: 10}
+
+	got := Regress(latest, history, DefaultRegressOptions())
+	if !got.Regressed() || len(got.History) != 5 {
+		t.Fatalf("expected a regression against 5 runs, got %+v", got)
+	}
+	byMetric := map[string]MetricCheck{}
+	for _, c := range got.Checks {
+		byMetric[c.Metric] = c
+	}
+	if c := byMetric[MetricBalance]; !c.Regressed || c.Z < 2 || c.Change < 0.5 {
+		t.Fatalf("expected balance regression, got %+v", c)
+	}
+	if c := byMetric[MetricRebalance]; !c.Regressed || c.Note != "did not rebalance" {
+		t.Fatalf("expected rebalance regression, got %+v", c)
+	}
+	if c := byMetric[MetricEvictions]; c.Regressed {
+		t.Fatalf("expected unchanged evictions to pass, got %+v", c)
+	}
+
+	latest = Entry{RunID: "z", AfterPodsStddev: 1.02, RebalanceTimeSeconds: 31, Evictions: 10}
+	if got := Regress(latest, history, DefaultRegressOptions()); got.Regressed() {
+		t.Fatalf("expected no regression within noise, got %+v", got.Checks)
+	}
+
+	got = Regress(latest, history[:2], DefaultRegressOptions())
+	if got.Regressed() || got.Checks[0].Note == "" {
+		t.Fatalf("expected too little history to be reported, not flagged: %+v", got.Checks)
+	}
+}
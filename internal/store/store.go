@@ -0,0 +1,251 @@
+package store
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/metrics"
+	"k8s-descheduler-benchmark/internal/report"
+)
+
+const (
+	// DefaultDir is where runs are archived unless told otherwise.
+	DefaultDir = "results/history"
+
+	indexFile = "index.jsonl"
+	runsDir   = "runs"
+)
+
+// ErrExists is returned by Add when the run ID is already stored.
+var ErrExists = errors.New("run already stored")
+
+// Entry is the index record of one stored run. It carries the summary
+// metrics regress compares, so listing and regression checks never need
+// to open the result files.
+type Entry struct {
+	RunID            string    `json:"run_id"`
+	StartTime        time.Time `json:"start_time"`
+	Scenario         string    `json:"scenario"`
+	Profile          string    `json:"profile"`
+	DeschedulerImage string    `json:"descheduler_image"`
+	// Cluster fingerprints the node set the run measured.
+	Cluster string `json:"cluster"`
+	// Config identifies runs that are comparable: same scenario, profile,
+	// image, workload and cluster.
+	Config string `json:"config"`
+	Status string `json:"status"`
+	// Path is the stored result file, relative to the store directory.
+	Path                 string  `json:"path"`
+	DurationSeconds      float64 `json:"duration_seconds"`
+	RebalanceTimeSeconds float64 `json:"rebalance_time_seconds"`
+	AfterPodsStddev      float64 `json:"after_pods_stddev"`
+	Evictions            int     `json:"evictions"`
+}
+
+// Filter selects entries in List. Empty fields match everything.
+type Filter struct {
+	Profile string
+	Image   string
+	Cluster string
+	Config  string
+	Status  string
+}
+
+// Match reports whether e passes every set field of f.
+func (f Filter) Match(e Entry) bool {
+	return (f.Profile == "" || e.Profile == f.Profile) &&
+		(f.Image == "" || e.DeschedulerImage == f.Image) &&
+		(f.Cluster == "" || strings.HasPrefix(e.Cluster, f.Cluster)) &&
+		(f.Config == "" || strings.HasPrefix(e.Config, f.Config)) &&
+		(f.Status == "" || e.Status == f.Status)
+}
+
+// Store archives result files under a directory: runs/<run_id>.json holds
+// each result and index.jsonl has one Entry per line, in the order runs
+// were added.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func New(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Store{dir: dir}
+}
+
+// Dir returns the store directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Add copies r into the store and indexes it.
+func (s *Store) Add(r report.Result) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := NewEntry(r)
+	if entry.RunID == "" {
+		return Entry{}, fmt.Errorf("result has no run_id")
+	}
+	entries, err := s.read()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, e := range entries {
+		if e.RunID == entry.RunID {
+			return e, fmt.Errorf("%s: %w", entry.RunID, ErrExists)
+		}
+	}
+
+	entry.Path = filepath.Join(runsDir, entry.RunID+".json")
+	if err := report.WriteResult(filepath.Join(s.dir, entry.Path), r); err != nil {
+		return Entry{}, err
+	}
+	file, err := os.OpenFile(filepath.Join(s.dir, indexFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer file.Close()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return Entry{}, err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// List returns the entries matching f, oldest first.
+func (s *Store) List(f Filter) ([]Entry, error) {
+	s.mu.Lock()
+	entries, err := s.read()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var out []Entry
+	for _, e := range entries {
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartTime.Before(out[j].StartTime) })
+	return out, nil
+}
+
+// Load reads the stored result of e.
+func (s *Store) Load(e Entry) (report.Result, error) {
+	return report.LoadResult(filepath.Join(s.dir, e.Path))
+}
+
+func (s *Store) read() ([]Entry, error) {
+	file, err := os.Open(filepath.Join(s.dir, indexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", indexFile, line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// NewEntry builds the index record of r. Path is left empty.
+func NewEntry(r report.Result) Entry {
+	cluster := ClusterFingerprint(r)
+	return Entry{
+		RunID:                r.Config.RunID,
+		StartTime:            r.Config.StartTime,
+		Scenario:             r.Config.Scenario,
+		Profile:              r.Config.Profile,
+		DeschedulerImage:     r.Config.DeschedulerImage,
+		Cluster:              cluster,
+		Config:               ConfigKey(r.Config, cluster),
+		Status:               r.Status,
+		DurationSeconds:      r.Summary.DurationSeconds,
+		RebalanceTimeSeconds: r.Summary.RebalanceTimeSeconds,
+		AfterPodsStddev:      r.Summary.After.PodsStddev,
+		Evictions:            len(r.Evictions),
+	}
+}
+
+// ClusterFingerprint hashes the names and allocatable resources of the
+// nodes in the run's before snapshot. The API server address is left out:
+// minikube picks a new port on every start.
+func ClusterFingerprint(r report.Result) string {
+	snap := r.BeforeSnapshot
+	if len(snap.Nodes) == 0 && len(r.Snapshots) > 0 {
+		snap = r.Snapshots[0]
+	}
+	names := make([]string, 0, len(snap.Nodes))
+	for name := range snap.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, nodeKey(name, snap.Nodes[name]))
+	}
+	return hash(parts...)
+}
+
+func nodeKey(name string, n metrics.NodeStats) string {
+	return fmt.Sprintf("%s/%d/%d", name, n.CPUAllocatableMilli, n.MemAllocatableBytes)
+}
+
+// ConfigKey hashes everything that must match for two runs to be compared.
+// Noise only enters the hash when set, so keys of runs without it stay the
+// same.
+func ConfigKey(cfg report.RunConfig, cluster string) string {
+	parts := []string{
+		cfg.Scenario,
+		cfg.Profile,
+		cfg.DeschedulerImage,
+		cfg.DeschedulerMode,
+		fmt.Sprint(cfg.PodsTotal),
+		cfg.PodCPU,
+		cfg.PodMemory,
+		cluster,
+	}
+	if cfg.NoiseNamespaces > 0 {
+		parts = append(parts, fmt.Sprint(cfg.NoiseNamespaces), cfg.NoiseMix)
+	}
+	if cfg.Mix != "" {
+		parts = append(parts, cfg.Mix)
+	}
+	if cfg.PriorityThreshold > 0 {
+		parts = append(parts, fmt.Sprint(cfg.PriorityThreshold))
+	}
+	return hash(parts...)
+}
+
+func hash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+	"k8s-descheduler-benchmark/internal/metrics"
+	"k8s-descheduler-benchmark/internal/report"
+)
+
+func storeFixture(runID string, start time.Time, image string) report.Result {
+	return report.Result{
+		Status: report.StatusSuccess,
+		Config: report.RunConfig{
+			RunID:            runID,
+			Scenario:         "maintenance",
+			Profile:          "low-node-utilization",
+			StartTime:        start,
+			Server:           "https://127.0.0.1:" + runID,
+			PodsTotal:        60,
+			DeschedulerImage: image,
+		},
+		Summary: report.Summary{RebalanceTimeSeconds: 30, After: metrics.Sample{PodsStddev: 1.2}},
+		BeforeSnapshot: metrics.Snapshot{Nodes: map[string]metrics.NodeStats{
+			"m02": {CPUAllocatableMilli: 2000, MemAllocatableBytes: 4 << 30},
+			"m03": {CPUAllocatableMilli: 2000, MemAllocatableBytes: 4 << 30},
+		}},
+		Evictions: []k8s.EvictionRecord{{PodName: "p-1"}, {PodName: "p-2"}},
+	}
+}
+
+func TestStoreAddAndList(t *testing.T) {
+	s := New(t.TempDir())
+	t0 := time.Date(2026, 2, 9, 2, 0, 0, 0, time.UTC)
+	older, err := s.Add(storeFixture("b", t0, "v0.32.2"))
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := s.Add(storeFixture("a", t0.Add(time.Hour), "v0.31.0")); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := s.Add(storeFixture("b", t0, "v0.32.2")); !errors.Is(err, ErrExists) {
+		t.Fatalf("expected ErrExists, got %v", err)
+	}
+
+	all, err := s.List(Filter{})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(all) != 2 || all[0].RunID != "b" || all[1].RunID != "a" {
+		t.Fatalf("expected runs oldest first, got %+v", all)
+	}
+	if all[0].Evictions != 2 || all[0].AfterPodsStddev != 1.2 || all[0].Path != "runs/b.json" {
+		t.Fatalf("unexpected entry: %+v", all[0])
+	}
+	// Server differs per run but the node set is the same.
+	if all[0].Cluster != all[1].Cluster {
+		t.Fatalf("expected the same cluster fingerprint")
+	}
+	if all[0].Config == all[1].Config {
+		t.Fatalf("expected different images to have different config keys")
+	}
+
+	filtered, err := s.List(Filter{Image: "v0.32.2", Config: older.Config[:4]})
+	if err != nil || len(filtered) != 1 || filtered[0].RunID != "b" {
+		t.Fatalf("unexpected filtered list %+v (err %v)", filtered, err)
+	}
+	result, err := s.Load(filtered[0])
+	if err != nil || result.Config.RunID != "b" || result.SchemaVersion != report.CurrentSchemaVersion {
+		t.Fatalf("unexpected stored result %+v (err %v)", result.Config, err)
+	}
+}
+
+func TestConfigKeyNoise(t *testing.T) {
+	cfg := storeFixture("a", time.Time{}, "v0.32.2").Config
+	quiet := ConfigKey(cfg, "c1")
+	cfg.NoiseNamespaces = 2
+	cfg.NoiseMix = "large=1,small=4"
+	noisy := ConfigKey(cfg, "c1")
+	if noisy == quiet {
+		t.Fatalf("expected noise to change the config key")
+	}
+	cfg.NoiseMix = "large=2,small=4"
+	if ConfigKey(cfg, "c1") == noisy {
+		t.Fatalf("expected the noise mix to change the config key")
+	}
+}
+
+func TestConfigKeyMix(t *testing.T) {
+	cfg := storeFixture("a", time.Time{}, "v0.32.2").Config
+	plain := ConfigKey(cfg, "c1")
+	cfg.Mix = "small=4,statefulset/small=2"
+	if ConfigKey(cfg, "c1") == plain {
+		t.Fatalf("expected the workload mix to change the config key")
+	}
+}
+
+func TestConfigKeyPriorityThreshold(t *testing.T) {
+	cfg := storeFixture("a", time.Time{}, "v0.32.2").Config
+	plain := ConfigKey(cfg, "c1")
+	cfg.PriorityThreshold = 100000
+	if ConfigKey(cfg, "c1") == plain {
+		t.Fatalf("expected the priority threshold to change the config key")
+	}
+}
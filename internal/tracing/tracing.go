@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ServiceName    = "deschedbench"
+	instrumentName = "k8s-descheduler-benchmark"
+)
+
+// Options selects where spans go. With neither field set tracing stays a
+// no-op and Setup returns a no-op shutdown.
+type Options struct {
+	// Endpoint is an OTLP/HTTP collector URL, e.g. http://localhost:4318.
+	Endpoint string
+	// File receives spans as JSON, one object per span, for offline use.
+	File string
+}
+
+func (o Options) Enabled() bool {
+	return o.Endpoint != "" || o.File != ""
+}
+
+// Setup installs the global tracer provider. The returned shutdown flushes
+// pending spans and closes the exporters; call it before the process exits.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if !opts.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var (
+		providerOpts []sdktrace.TracerProviderOption
+		closers      []func() error
+	)
+	if opts.Endpoint != "" {
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(opts.Endpoint))
+		if err != nil {
+			return nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+		providerOpts = append(providerOpts, sdktrace.WithBatcher(exporter))
+	}
+	if opts.File != "" {
+		f, err := os.Create(opts.File)
+		if err != nil {
+			return nil, fmt.Errorf("create trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("create file exporter: %w", err)
+		}
+		providerOpts = append(providerOpts, sdktrace.WithSyncer(exporter))
+		closers = append(closers, f.Close)
+	}
+	providerOpts = append(providerOpts, sdktrace.WithResource(resource.NewSchemaless(
+		attribute.String("service.name", ServiceName),
+	)))
+
+	provider := sdktrace.NewTracerProvider(providerOpts...)
+	otel.SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		for _, closeFn := range closers {
+			err = errors.Join(err, closeFn())
+		}
+		return err
+	}, nil
+}
+
+// Tracer returns the benchmark tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentName)
+}
+
+// End marks span as failed when err is non-nil and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
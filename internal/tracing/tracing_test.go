@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetupFileExporter(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	shutdown, err := Setup(context.Background(), Options{File: path})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	ctx, root := Tracer().Start(context.Background(), "benchmark")
+	_, step := Tracer().Start(ctx, "drain")
+	End(step, errors.New("drain timed out"))
+	End(root, nil)
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read trace file: %v", err)
+	}
+	type span struct {
+		Name        string
+		SpanContext struct{ TraceID string }
+		Parent      struct{ SpanID string }
+		Status      struct{ Code string }
+	}
+	var spans []span
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	for dec.More() {
+		var s span
+		if err := dec.Decode(&s); err != nil {
+			t.Fatalf("decode span: %v", err)
+		}
+		spans = append(spans, s)
+	}
+	if len(spans) != 2 || spans[0].Name != "drain" || spans[1].Name != "benchmark" {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+	if spans[0].SpanContext.TraceID != spans[1].SpanContext.TraceID {
+		t.Fatalf("expected step and run in the same trace")
+	}
+	if spans[0].Status.Code != "Error" {
+		t.Fatalf("expected failed step to carry error status, got %q", spans[0].Status.Code)
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
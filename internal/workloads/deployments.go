@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"k8s-descheduler-benchmark/internal/k8s"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -23,30 +25,83 @@ type WorkloadConfig struct {
 	Namespace      string
 	NamePrefix     string
 	Labels         map[string]string
+	Annotations    map[string]string
 	Mix            Mix
 	SizeClasses    map[string]SizeClass
 	PodImage       string
 	PodAnnotations map[string]string
 	PodLabels      map[string]string
+	// PriorityClasses maps each priority tier of Mix to the PriorityClass
+	// its pods run with. The classes must exist before the pods.
+	PriorityClasses map[string]string
+}
+
+// workload is one mix entry resolved against its config.
+type workload struct {
+	name          string
+	kind          string
+	tier          string
+	priorityClass string
+	size          SizeClass
+	count         int32
 }
 
+func resolveWorkload(cfg WorkloadConfig, key string, count int32) (workload, error) {
+	kind, className := SplitMixKey(key)
+	size, ok := cfg.SizeClasses[className]
+	if !ok {
+		return workload{}, fmt.Errorf("size class %q not defined", className)
+	}
+	w := workload{
+		name:  ObjectName(cfg.NamePrefix, key),
+		kind:  kind,
+		tier:  MixTier(key),
+		size:  size,
+		count: count,
+	}
+	if w.tier != "" {
+		if w.priorityClass = cfg.PriorityClasses[w.tier]; w.priorityClass == "" {
+			return workload{}, fmt.Errorf("priority tier %q has no PriorityClass", w.tier)
+		}
+	}
+	return w, nil
+}
+
+// EnsureWorkloads creates or updates one workload per mix key, of the kind
+// the key names.
 func EnsureWorkloads(ctx context.Context, client kubernetes.Interface, cfg WorkloadConfig) error {
-	for className, count := range cfg.Mix {
+	for key, count := range cfg.Mix {
 		if count == 0 {
 			continue
 		}
-		size, ok := cfg.SizeClasses[className]
-		if !ok {
-			return fmt.Errorf("size class %q not defined", className)
+		w, err := resolveWorkload(cfg, key, count)
+		if err != nil {
+			return err
+		}
+		switch w.kind {
+		case k8s.KindDeployment:
+			err = ensureDeployment(ctx, client, cfg, w)
+		case k8s.KindStatefulSet:
+			err = ensureStatefulSet(ctx, client, cfg, w)
+		case k8s.KindReplicaSet:
+			err = ensureReplicaSet(ctx, client, cfg, w)
+		case k8s.KindJob:
+			err = ensureJob(ctx, client, cfg, w)
+		case k8s.KindPod:
+			err = ensurePods(ctx, client, cfg, w)
+		default:
+			err = fmt.Errorf("unknown workload kind %q", w.kind)
 		}
-		name := fmt.Sprintf("%s-%s", cfg.NamePrefix, className)
-		if err := ensureDeployment(ctx, client, cfg, name, size, count); err != nil {
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// WaitForWorkloadsReady waits until every workload of mix reports all its
+// pods ready: ready replicas for Deployments, StatefulSets and ReplicaSets,
+// ready pods for Jobs, and the Ready condition for bare pods.
 func WaitForWorkloadsReady(ctx context.Context, client kubernetes.Interface, namespace, namePrefix string, mix Mix, timeout time.Duration) error {
 	if timeout <= 0 {
 		timeout = 10 * time.Minute
@@ -54,29 +109,83 @@ func WaitForWorkloadsReady(ctx context.Context, client kubernetes.Interface, nam
 	return wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
 		var desired int32
 		var ready int32
-		for className, count := range mix {
+		for key, count := range mix {
 			if count == 0 {
 				continue
 			}
 			desired += count
-			name := fmt.Sprintf("%s-%s", namePrefix, className)
-			dep, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+			kind, _ := SplitMixKey(key)
+			n, err := readyPods(ctx, client, namespace, kind, ObjectName(namePrefix, key))
 			if err != nil {
 				return false, err
 			}
-			ready += dep.Status.ReadyReplicas
+			ready += n
 		}
 		return ready == desired, nil
 	})
 }
 
-func ensureDeployment(ctx context.Context, client kubernetes.Interface, cfg WorkloadConfig, name string, size SizeClass, replicas int32) error {
+// ObjectName names the workload of a mix key: "<prefix>-<class>" for
+// Deployments and "<prefix>-<kind>-<class>" for the other kinds, with
+// "-<tier>" appended for a priority tier.
+func ObjectName(prefix, key string) string {
+	kind, class := SplitMixKey(key)
+	name := fmt.Sprintf("%s-%s-%s", prefix, kind, class)
+	if kind == k8s.KindDeployment {
+		name = fmt.Sprintf("%s-%s", prefix, class)
+	}
+	if tier := MixTier(key); tier != "" {
+		name += "-" + tier
+	}
+	return name
+}
+
+func ensureDeployment(ctx context.Context, client kubernetes.Interface, cfg WorkloadConfig, w workload) error {
+	dep := buildDeployment(cfg, w)
+	existing, err := client.AppsV1().Deployments(cfg.Namespace).Get(ctx, w.name, metav1.GetOptions{})
+	if err == nil && existing != nil {
+		dep.ResourceVersion = existing.ResourceVersion
+		_, err = client.AppsV1().Deployments(cfg.Namespace).Update(ctx, dep, metav1.UpdateOptions{})
+		return err
+	}
+	_, err = client.AppsV1().Deployments(cfg.Namespace).Create(ctx, dep, metav1.CreateOptions{})
+	return err
+}
+
+func buildDeployment(cfg WorkloadConfig, w workload) *appsv1.Deployment {
+	labels := objectLabels(cfg, w.name)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        w.name,
+			Namespace:   cfg.Namespace,
+			Labels:      labels,
+			Annotations: cfg.Annotations,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &w.count,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: podTemplate(cfg, labels, w),
+		},
+	}
+
+	return dep
+}
+
+// objectLabels are the labels of the workload object and its selector.
+func objectLabels(cfg WorkloadConfig, name string) map[string]string {
 	labels := map[string]string{}
 	for k, v := range cfg.Labels {
 		labels[k] = v
 	}
 	labels["app.kubernetes.io/name"] = name
+	return labels
+}
 
+// podTemplate is the pause pod every kind runs. Its labels add the pod
+// labels, the workload kind and the priority tier to the object labels.
+func podTemplate(cfg WorkloadConfig, labels map[string]string, w workload) corev1.PodTemplateSpec {
 	podLabels := map[string]string{}
 	for k, v := range labels {
 		podLabels[k] = v
@@ -84,55 +193,38 @@ func ensureDeployment(ctx context.Context, client kubernetes.Interface, cfg Work
 	for k, v := range cfg.PodLabels {
 		podLabels[k] = v
 	}
+	podLabels[k8s.KindLabel] = w.kind
+	if w.tier != "" {
+		podLabels[k8s.PriorityLabel] = w.tier
+	}
 
 	podAnnotations := map[string]string{}
 	for k, v := range cfg.PodAnnotations {
 		podAnnotations[k] = v
 	}
 
-	cpuQty := resource.MustParse(size.CPU)
-	memQty := resource.MustParse(size.Memory)
+	cpuQty := resource.MustParse(w.size.CPU)
+	memQty := resource.MustParse(w.size.Memory)
 
-	dep := &appsv1.Deployment{
+	return corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: cfg.Namespace,
-			Labels:    labels,
+			Labels:      podLabels,
+			Annotations: podAnnotations,
 		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
-			Selector: &metav1.LabelSelector{
-				MatchLabels: labels,
-			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels:      podLabels,
-					Annotations: podAnnotations,
-				},
-				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:  "pause",
-							Image: cfg.PodImage,
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    cpuQty,
-									corev1.ResourceMemory: memQty,
-								},
-							},
+		Spec: corev1.PodSpec{
+			PriorityClassName: w.priorityClass,
+			Containers: []corev1.Container{
+				{
+					Name:  "pause",
+					Image: cfg.PodImage,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    cpuQty,
+							corev1.ResourceMemory: memQty,
 						},
 					},
 				},
 			},
 		},
 	}
-
-	existing, err := client.AppsV1().Deployments(cfg.Namespace).Get(ctx, name, metav1.GetOptions{})
-	if err == nil && existing != nil {
-		dep.ResourceVersion = existing.ResourceVersion
-		_, err = client.AppsV1().Deployments(cfg.Namespace).Update(ctx, dep, metav1.UpdateOptions{})
-		return err
-	}
-	_, err = client.AppsV1().Deployments(cfg.Namespace).Create(ctx, dep, metav1.CreateOptions{})
-	return err
 }
@@ -181,3 +181,34 @@ func TestWaitForWorkloadsReady(t *testing.T) {
 func int32Ptr(val int32) *int32 {
 	return &val
 }
+
+func TestEnsureWorkloadsPriorityTier(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	cfg := WorkloadConfig{
+		Namespace:       "test",
+		NamePrefix:      "bench",
+		Mix:             Mix{"small@high": 2},
+		SizeClasses:     map[string]SizeClass{"small": {Name: "small", CPU: "100m", Memory: "128Mi"}},
+		PodImage:        "registry.k8s.io/pause:3.9",
+		PriorityClasses: map[string]string{"high": "deschedbench-r1-high"},
+	}
+	if err := EnsureWorkloads(ctx, client, cfg); err != nil {
+		t.Fatalf("EnsureWorkloads failed: %v", err)
+	}
+	dep, err := client.AppsV1().Deployments("test").Get(ctx, "bench-small-high", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected deployment created: %v", err)
+	}
+	if dep.Spec.Template.Spec.PriorityClassName != "deschedbench-r1-high" {
+		t.Fatalf("unexpected priority class %q", dep.Spec.Template.Spec.PriorityClassName)
+	}
+	if dep.Spec.Template.Labels["deschedbench/priority"] != "high" {
+		t.Fatalf("expected priority label on template, got %v", dep.Spec.Template.Labels)
+	}
+
+	cfg.PriorityClasses = nil
+	if err := EnsureWorkloads(ctx, client, cfg); err == nil {
+		t.Fatal("expected error for a tier without a PriorityClass")
+	}
+}
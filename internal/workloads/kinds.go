@@ -0,0 +1,264 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// BuildObjects renders what EnsureWorkloads would apply, without touching
+// the cluster, ordered by mix key. Each StatefulSet is preceded by its
+// headless Service and bare pods are listed one by one. TypeMeta is set so
+// the objects marshal as manifests.
+func BuildObjects(cfg WorkloadConfig) ([]runtime.Object, error) {
+	keys := make([]string, 0, len(cfg.Mix))
+	for key := range cfg.Mix {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var out []runtime.Object
+	for _, key := range keys {
+		count := cfg.Mix[key]
+		if count == 0 {
+			continue
+		}
+		w, err := resolveWorkload(cfg, key, count)
+		if err != nil {
+			return nil, err
+		}
+		switch w.kind {
+		case k8s.KindDeployment:
+			dep := buildDeployment(cfg, w)
+			dep.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+			out = append(out, dep)
+		case k8s.KindStatefulSet:
+			svc := buildHeadlessService(cfg, w.name)
+			svc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+			sts := buildStatefulSet(cfg, w)
+			sts.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"}
+			out = append(out, svc, sts)
+		case k8s.KindReplicaSet:
+			rs := buildReplicaSet(cfg, w)
+			rs.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSet"}
+			out = append(out, rs)
+		case k8s.KindJob:
+			job := buildJob(cfg, w)
+			job.TypeMeta = metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"}
+			out = append(out, job)
+		case k8s.KindPod:
+			for _, pod := range buildPods(cfg, w) {
+				pod.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}
+				out = append(out, pod)
+			}
+		default:
+			return nil, fmt.Errorf("unknown workload kind %q", w.kind)
+		}
+	}
+	return out, nil
+}
+
+// buildHeadlessService gives the StatefulSet of the same name its stable
+// network identity.
+func buildHeadlessService(cfg WorkloadConfig, name string) *corev1.Service {
+	labels := objectLabels(cfg, name)
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   cfg.Namespace,
+			Labels:      labels,
+			Annotations: cfg.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  labels,
+		},
+	}
+}
+
+// buildStatefulSet uses parallel pod management so startup does not wait on
+// each ordinal in turn.
+func buildStatefulSet(cfg WorkloadConfig, w workload) *appsv1.StatefulSet {
+	labels := objectLabels(cfg, w.name)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        w.name,
+			Namespace:   cfg.Namespace,
+			Labels:      labels,
+			Annotations: cfg.Annotations,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:            &w.count,
+			ServiceName:         w.name,
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: podTemplate(cfg, labels, w),
+		},
+	}
+}
+
+func buildReplicaSet(cfg WorkloadConfig, w workload) *appsv1.ReplicaSet {
+	labels := objectLabels(cfg, w.name)
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        w.name,
+			Namespace:   cfg.Namespace,
+			Labels:      labels,
+			Annotations: cfg.Annotations,
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: &w.count,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: podTemplate(cfg, labels, w),
+		},
+	}
+}
+
+// buildJob runs w.count pause pods in parallel; they never complete. Evicted
+// pods are replaced without counting against the backoff limit, through a
+// pod failure policy that ignores disruptions.
+func buildJob(cfg WorkloadConfig, w workload) *batchv1.Job {
+	labels := objectLabels(cfg, w.name)
+	template := podTemplate(cfg, labels, w)
+	template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        w.name,
+			Namespace:   cfg.Namespace,
+			Labels:      labels,
+			Annotations: cfg.Annotations,
+		},
+		Spec: batchv1.JobSpec{
+			Parallelism: &w.count,
+			Completions: &w.count,
+			PodFailurePolicy: &batchv1.PodFailurePolicy{
+				Rules: []batchv1.PodFailurePolicyRule{{
+					Action: batchv1.PodFailurePolicyActionIgnore,
+					OnPodConditions: []batchv1.PodFailurePolicyOnPodConditionsPattern{{
+						Type:   corev1.DisruptionTarget,
+						Status: corev1.ConditionTrue,
+					}},
+				}},
+			},
+			Template: template,
+		},
+	}
+}
+
+// buildPods returns w.count bare pods named <name>-<i>. Nothing recreates
+// them once evicted.
+func buildPods(cfg WorkloadConfig, w workload) []*corev1.Pod {
+	labels := objectLabels(cfg, w.name)
+	template := podTemplate(cfg, labels, w)
+	out := make([]*corev1.Pod, 0, w.count)
+	for i := int32(0); i < w.count; i++ {
+		meta := *template.ObjectMeta.DeepCopy()
+		meta.Name = fmt.Sprintf("%s-%d", w.name, i)
+		meta.Namespace = cfg.Namespace
+		out = append(out, &corev1.Pod{ObjectMeta: meta, Spec: *template.Spec.DeepCopy()})
+	}
+	return out
+}
+
+func ensureStatefulSet(ctx context.Context, client kubernetes.Interface, cfg WorkloadConfig, w workload) error {
+	svc := buildHeadlessService(cfg, w.name)
+	if _, err := client.CoreV1().Services(cfg.Namespace).Create(ctx, svc, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	sts := buildStatefulSet(cfg, w)
+	existing, err := client.AppsV1().StatefulSets(cfg.Namespace).Get(ctx, w.name, metav1.GetOptions{})
+	if err == nil && existing != nil {
+		sts.ResourceVersion = existing.ResourceVersion
+		_, err = client.AppsV1().StatefulSets(cfg.Namespace).Update(ctx, sts, metav1.UpdateOptions{})
+		return err
+	}
+	_, err = client.AppsV1().StatefulSets(cfg.Namespace).Create(ctx, sts, metav1.CreateOptions{})
+	return err
+}
+
+func ensureReplicaSet(ctx context.Context, client kubernetes.Interface, cfg WorkloadConfig, w workload) error {
+	rs := buildReplicaSet(cfg, w)
+	existing, err := client.AppsV1().ReplicaSets(cfg.Namespace).Get(ctx, w.name, metav1.GetOptions{})
+	if err == nil && existing != nil {
+		rs.ResourceVersion = existing.ResourceVersion
+		_, err = client.AppsV1().ReplicaSets(cfg.Namespace).Update(ctx, rs, metav1.UpdateOptions{})
+		return err
+	}
+	_, err = client.AppsV1().ReplicaSets(cfg.Namespace).Create(ctx, rs, metav1.CreateOptions{})
+	return err
+}
+
+// ensureJob creates the Job once. A Job's template is immutable, so an
+// existing Job is kept as it is.
+func ensureJob(ctx context.Context, client kubernetes.Interface, cfg WorkloadConfig, w workload) error {
+	job := buildJob(cfg, w)
+	_, err := client.BatchV1().Jobs(cfg.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func ensurePods(ctx context.Context, client kubernetes.Interface, cfg WorkloadConfig, w workload) error {
+	for _, pod := range buildPods(cfg, w) {
+		_, err := client.CoreV1().Pods(cfg.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// readyPods counts the ready pods of the workload called name.
+func readyPods(ctx context.Context, client kubernetes.Interface, namespace, kind, name string) (int32, error) {
+	switch kind {
+	case k8s.KindDeployment:
+		dep, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return dep.Status.ReadyReplicas, nil
+	case k8s.KindStatefulSet:
+		sts, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return sts.Status.ReadyReplicas, nil
+	case k8s.KindReplicaSet:
+		rs, err := client.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return rs.Status.ReadyReplicas, nil
+	case k8s.KindJob:
+		job, err := client.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		if job.Status.Ready == nil {
+			return 0, nil
+		}
+		return *job.Status.Ready, nil
+	case k8s.KindPod:
+		summary, err := k8s.SummarizeScheduling(ctx, client, namespace, "app.kubernetes.io/name="+name)
+		if err != nil {
+			return 0, err
+		}
+		return summary.Ready, nil
+	default:
+		return 0, fmt.Errorf("unknown workload kind %q", kind)
+	}
+}
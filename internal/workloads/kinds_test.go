@@ -0,0 +1,140 @@
+package workloads
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s-descheduler-benchmark/internal/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func kindsConfig() WorkloadConfig {
+	return WorkloadConfig{
+		Namespace:  "test",
+		NamePrefix: "bench",
+		Labels:     map[string]string{"deschedbench": "true"},
+		Mix: Mix{
+			"small":             1,
+			"statefulset/small": 2,
+			"replicaset/small":  2,
+			"job/small":         2,
+			"pod/small":         2,
+		},
+		SizeClasses: map[string]SizeClass{
+			"small": {Name: "small", CPU: "100m", Memory: "128Mi"},
+		},
+		PodImage: "registry.k8s.io/pause:3.9",
+	}
+}
+
+func TestEnsureWorkloadsKinds(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	cfg := kindsConfig()
+
+	if err := EnsureWorkloads(ctx, client, cfg); err != nil {
+		t.Fatalf("EnsureWorkloads failed: %v", err)
+	}
+
+	sts, err := client.AppsV1().StatefulSets("test").Get(ctx, "bench-statefulset-small", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected statefulset created: %v", err)
+	}
+	if sts.Spec.ServiceName != sts.Name || *sts.Spec.Replicas != 2 {
+		t.Fatalf("unexpected statefulset spec: %+v", sts.Spec)
+	}
+	if sts.Spec.Template.Labels[k8s.KindLabel] != k8s.KindStatefulSet {
+		t.Fatalf("expected kind label on statefulset pods")
+	}
+	svc, err := client.CoreV1().Services("test").Get(ctx, "bench-statefulset-small", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected headless service created: %v", err)
+	}
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		t.Fatalf("expected headless service, got cluster IP %q", svc.Spec.ClusterIP)
+	}
+	if _, err := client.AppsV1().ReplicaSets("test").Get(ctx, "bench-replicaset-small", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected replicaset created: %v", err)
+	}
+	job, err := client.BatchV1().Jobs("test").Get(ctx, "bench-job-small", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected job created: %v", err)
+	}
+	if *job.Spec.Parallelism != 2 || job.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Fatalf("unexpected job spec: %+v", job.Spec)
+	}
+	pods, err := client.CoreV1().Pods("test").List(ctx, metav1.ListOptions{})
+	if err != nil || len(pods.Items) != 2 {
+		t.Fatalf("expected 2 bare pods, got %d (%v)", len(pods.Items), err)
+	}
+	if _, err := client.AppsV1().Deployments("test").Get(ctx, "bench-small", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected deployment created: %v", err)
+	}
+
+	// A second pass leaves existing Jobs, Services and pods in place.
+	if err := EnsureWorkloads(ctx, client, cfg); err != nil {
+		t.Fatalf("second EnsureWorkloads failed: %v", err)
+	}
+}
+
+func TestWaitForWorkloadsReadyKinds(t *testing.T) {
+	ctx := context.Background()
+	ready := int32(2)
+	readyPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels:    map[string]string{"app.kubernetes.io/name": "bench-pod-small"},
+			},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "bench-small", Namespace: "test"}, Status: appsv1.DeploymentStatus{ReadyReplicas: 1}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "bench-statefulset-small", Namespace: "test"}, Status: appsv1.StatefulSetStatus{ReadyReplicas: 2}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "bench-replicaset-small", Namespace: "test"}, Status: appsv1.ReplicaSetStatus{ReadyReplicas: 2}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "bench-job-small", Namespace: "test"}, Status: batchv1.JobStatus{Ready: &ready}},
+		readyPod("bench-pod-small-0"),
+		readyPod("bench-pod-small-1"),
+	)
+
+	cfg := kindsConfig()
+	if err := WaitForWorkloadsReady(ctx, client, "test", "bench", cfg.Mix, 2*time.Second); err != nil {
+		t.Fatalf("WaitForWorkloadsReady failed: %v", err)
+	}
+
+	cfg.Mix["pod/small"] = 3
+	if err := WaitForWorkloadsReady(ctx, client, "test", "bench", cfg.Mix, 100*time.Millisecond); err == nil {
+		t.Fatal("expected timeout with a missing bare pod")
+	}
+}
+
+func TestBuildObjects(t *testing.T) {
+	objects, err := BuildObjects(kindsConfig())
+	if err != nil {
+		t.Fatalf("BuildObjects failed: %v", err)
+	}
+	var kinds []string
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	want := []string{"Job", "Pod", "Pod", "ReplicaSet", "Deployment", "Service", "StatefulSet"}
+	if len(kinds) != len(want) {
+		t.Fatalf("unexpected objects: %v", kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("unexpected objects: %v", kinds)
+		}
+	}
+}
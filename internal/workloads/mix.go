@@ -2,14 +2,25 @@ package workloads
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+
+	"k8s-descheduler-benchmark/internal/k8s"
 )
 
+// Mix counts pods per key. A key is a size class for Deployments, or
+// "<kind>/<class>" for the other workload kinds, e.g. "statefulset/small".
+// Either form may end in "@<tier>" to run the pods at a priority tier, e.g.
+// "small@critical".
 type Mix map[string]int32
 
+// ParseMix parses "small=10,statefulset/medium=3,pod/large@low=1". Kinds
+// accept the kubectl short names (deploy, sts, rs). Two entries that name
+// the same key, such as "small" and "deploy/small", are an error.
 func ParseMix(input string) (Mix, error) {
 	mix := Mix{}
+	seen := map[string]string{}
 	input = strings.TrimSpace(input)
 	if input == "" {
 		return mix, nil
@@ -20,10 +31,14 @@ func ParseMix(input string) (Mix, error) {
 		if len(kv) != 2 {
 			return nil, fmt.Errorf("invalid mix entry %q", part)
 		}
-		key := normalizeMixKey(kv[0])
-		if key == "" {
-			return nil, fmt.Errorf("unknown size class %q", kv[0])
+		key, err := normalizeMixKey(kv[0])
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := seen[key]; ok {
+			return nil, fmt.Errorf("duplicate mix entry %q: same as %q", strings.TrimSpace(kv[0]), prev)
 		}
+		seen[key] = strings.TrimSpace(kv[0])
 		val, err := strconv.Atoi(strings.TrimSpace(kv[1]))
 		if err != nil || val < 0 {
 			return nil, fmt.Errorf("invalid mix count for %q", kv[0])
@@ -33,7 +48,108 @@ func ParseMix(input string) (Mix, error) {
 	return mix, nil
 }
 
-func normalizeMixKey(key string) string {
+func normalizeMixKey(key string) (string, error) {
+	key, tierName, hasTier := strings.Cut(strings.TrimSpace(key), "@")
+	tier := ""
+	if hasTier {
+		if tier = normalizeTier(tierName); tier == "" {
+			return "", fmt.Errorf("unknown priority tier %q", tierName)
+		}
+	}
+	kindName, className, hasKind := strings.Cut(key, "/")
+	if !hasKind {
+		kindName, className = "", kindName
+	}
+	class := normalizeClass(className)
+	if class == "" {
+		return "", fmt.Errorf("unknown size class %q", className)
+	}
+	kind := k8s.KindDeployment
+	if hasKind {
+		if kind = normalizeKind(kindName); kind == "" {
+			return "", fmt.Errorf("unknown workload kind %q", kindName)
+		}
+	}
+	return MixKey(kind, class, tier), nil
+}
+
+func normalizeKind(kind string) string {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "deployment", "deploy":
+		return k8s.KindDeployment
+	case "statefulset", "sts":
+		return k8s.KindStatefulSet
+	case "replicaset", "rs":
+		return k8s.KindReplicaSet
+	case "job":
+		return k8s.KindJob
+	case "pod", "po":
+		return k8s.KindPod
+	default:
+		return ""
+	}
+}
+
+func normalizeTier(tier string) string {
+	switch strings.ToLower(strings.TrimSpace(tier)) {
+	case "low":
+		return "low"
+	case "high":
+		return "high"
+	case "critical", "crit":
+		return "critical"
+	default:
+		return ""
+	}
+}
+
+// MixKey builds the key of kind, class and priority tier. Deployments use
+// the bare class and an empty tier adds nothing, so Deployment-only mixes
+// and their object names are unchanged.
+func MixKey(kind, class, tier string) string {
+	key := class
+	if kind != k8s.KindDeployment {
+		key = kind + "/" + class
+	}
+	if tier != "" {
+		key += "@" + tier
+	}
+	return key
+}
+
+// SplitMixKey returns the workload kind and size class of a mix key.
+func SplitMixKey(key string) (string, string) {
+	key, _, _ = strings.Cut(key, "@")
+	if kind, class, ok := strings.Cut(key, "/"); ok {
+		return kind, class
+	}
+	return k8s.KindDeployment, key
+}
+
+// MixTier returns the priority tier of a mix key, or "" when the pods run
+// without a PriorityClass.
+func MixTier(key string) string {
+	_, tier, _ := strings.Cut(key, "@")
+	return tier
+}
+
+// MixTiers lists the priority tiers the mix uses, sorted.
+func MixTiers(mix Mix) []string {
+	seen := map[string]bool{}
+	var tiers []string
+	for key, count := range mix {
+		tier := MixTier(key)
+		if tier == "" || count == 0 || seen[tier] {
+			continue
+		}
+		seen[tier] = true
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+	return tiers
+}
+
+func normalizeClass(key string) string {
 	switch strings.ToLower(strings.TrimSpace(key)) {
 	case "small", "s":
 		return "small"
@@ -53,3 +169,36 @@ func MixTotal(mix Mix) int32 {
 	}
 	return total
 }
+
+// String renders the mix in the form ParseMix accepts, classes sorted.
+func (m Mix) String() string {
+	classes := make([]string, 0, len(m))
+	for class := range m {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	parts := make([]string, 0, len(classes))
+	for _, class := range classes {
+		parts = append(parts, fmt.Sprintf("%s=%d", class, m[class]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// DefaultPriorityTiers are the PriorityClass values of the tiers ParseMix
+// accepts. All sit below the system-* classes.
+func DefaultPriorityTiers() map[string]int32 {
+	return map[string]int32{
+		"low":      1000,
+		"high":     100000,
+		"critical": 1000000,
+	}
+}
+
+// DefaultSizeClasses are the requests of the classes ParseMix accepts.
+func DefaultSizeClasses() map[string]SizeClass {
+	return map[string]SizeClass{
+		"small":  {Name: "small", CPU: "100m", Memory: "128Mi"},
+		"medium": {Name: "medium", CPU: "250m", Memory: "256Mi"},
+		"large":  {Name: "large", CPU: "500m", Memory: "512Mi"},
+	}
+}
@@ -1,6 +1,9 @@
 package workloads
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestParseMixValid(t *testing.T) {
 	mix, err := ParseMix("small=2,med=3,large=1")
@@ -21,9 +24,91 @@ func TestParseMixInvalid(t *testing.T) {
 	}
 }
 
+func TestParseMixRejectsDuplicates(t *testing.T) {
+	for _, input := range []string{
+		"small=10,deploy/small=5",
+		"sts/small=3,statefulset/small=4",
+		"small@crit=1,deployment/small@critical=2",
+		"med=1,medium=2",
+	} {
+		_, err := ParseMix(input)
+		if err == nil || !strings.Contains(err.Error(), "duplicate mix entry") {
+			t.Fatalf("%s: expected a duplicate entry error, got %v", input, err)
+		}
+	}
+	if _, err := ParseMix("small=1,small@low=2,sts/small=3"); err != nil {
+		t.Fatalf("expected distinct keys to parse: %v", err)
+	}
+}
+
 func TestMixTotal(t *testing.T) {
 	mix := Mix{"small": 2, "medium": 3, "large": 4}
 	if total := MixTotal(mix); total != 9 {
 		t.Fatalf("expected total 9, got %d", total)
 	}
 }
+
+func TestMixString(t *testing.T) {
+	mix, err := ParseMix("l=1, s=4,medium=2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := mix.String(); got != "large=1,medium=2,small=4" {
+		t.Fatalf("unexpected mix string: %s", got)
+	}
+	roundTrip, err := ParseMix(mix.String())
+	if err != nil || MixTotal(roundTrip) != 7 {
+		t.Fatalf("round trip failed: %#v %v", roundTrip, err)
+	}
+}
+
+func TestDefaultSizeClassesCoverMix(t *testing.T) {
+	classes := DefaultSizeClasses()
+	for _, name := range []string{"small", "medium", "large"} {
+		if classes[name].Name != name {
+			t.Fatalf("missing size class %s", name)
+		}
+	}
+}
+
+func TestParseMixKinds(t *testing.T) {
+	mix, err := ParseMix("small=4,sts/s=2,ReplicaSet/medium=1,job/small=3,po/large=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Mix{"small": 4, "statefulset/small": 2, "replicaset/medium": 1, "job/small": 3, "pod/large": 1}
+	if mix.String() != want.String() {
+		t.Fatalf("unexpected mix: %s", mix.String())
+	}
+	if kind, class := SplitMixKey("statefulset/small"); kind != "statefulset" || class != "small" {
+		t.Fatalf("unexpected split: %s %s", kind, class)
+	}
+	if kind, class := SplitMixKey("small"); kind != "deployment" || class != "small" {
+		t.Fatalf("unexpected split: %s %s", kind, class)
+	}
+	if _, err := ParseMix("daemonset/small=1"); err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+}
+
+func TestParseMixTiers(t *testing.T) {
+	mix, err := ParseMix("small@low=4,sts/small@crit=2,medium=1,job/small@high=0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mix["small@low"] != 4 || mix["statefulset/small@critical"] != 2 || mix["medium"] != 1 {
+		t.Fatalf("unexpected mix: %s", mix.String())
+	}
+	if kind, class := SplitMixKey("statefulset/small@critical"); kind != "statefulset" || class != "small" {
+		t.Fatalf("unexpected split: %s %s", kind, class)
+	}
+	if tier := MixTier("statefulset/small@critical"); tier != "critical" {
+		t.Fatalf("unexpected tier: %q", tier)
+	}
+	if tiers := MixTiers(mix); strings.Join(tiers, ",") != "critical,low" {
+		t.Fatalf("unexpected tiers: %v", tiers)
+	}
+	if _, err := ParseMix("small@urgent=1"); err == nil {
+		t.Fatal("expected error for unknown tier")
+	}
+}